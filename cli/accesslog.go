@@ -0,0 +1,92 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+	"github.com/minio/warp/pkg/accesslog"
+	"github.com/minio/warp/pkg/bench"
+)
+
+// newAccessLog returns the access log writer specified by the context.
+// If no log path has been specified nil is returned.
+func newAccessLog(ctx *cli.Context) *accesslog.Writer {
+	path := ctx.String("logpath")
+	if path == "" {
+		return nil
+	}
+	opts := accesslog.DefaultOptions(path)
+	if s := ctx.String("log.maxsize"); s != "" {
+		size, err := toSize(s)
+		fatalIf(probe.NewError(err), "Invalid log.maxsize specified")
+		opts.MaxSize = int64(size)
+	}
+	opts.MaxAge = ctx.Duration("log.maxage")
+	opts.Compress = ctx.Bool("log.compress")
+	opts.OnError = func(err error) {
+		console.Errorln("Access log:", err)
+	}
+	w, err := accesslog.New(opts)
+	fatalIf(probe.NewError(err), "Unable to create access log")
+	return w
+}
+
+// errInterrupted is returned when a benchmark has been stopped by a signal.
+var errInterrupted = errors.New("benchmark interrupted")
+
+// cancelOnSignal returns a context that is canceled when the process is interrupted,
+// so the benchmark stops and its results are saved and cleaned up as usual.
+// The access log is flushed when the benchmark has stopped.
+// If the process is interrupted again the access log is flushed and the process exits.
+// The returned function must be called when the benchmark is done to stop listening.
+func cancelOnSignal(parent context.Context, c *bench.Common) (ctx context.Context, stop func()) {
+	ctx, cancel := context.WithCancel(parent)
+	sig := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-sig:
+			console.Errorln("Interrupted, stopping benchmark. Interrupt again to exit immediately.")
+			cancel()
+		case <-done:
+			return
+		}
+		select {
+		case <-sig:
+			if err := c.CloseAccessLog(); err != nil {
+				console.Errorln("Error writing access log:", err)
+			}
+			os.Exit(1)
+		case <-done:
+		}
+	}()
+	return ctx, func() {
+		signal.Stop(sig)
+		close(done)
+		cancel()
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -53,6 +54,11 @@ var analyzeFlags = []cli.Flag{
 		Value: "",
 		Usage: "Only output for this op. Can be GET/PUT/DELETE, etc.",
 	},
+	cli.StringFlag{
+		Name:  "analyze.phase",
+		Value: "",
+		Usage: "Only output for this scenario phase. By default each phase is analyzed separately.",
+	},
 	cli.StringFlag{
 		Name:  "analyze.host",
 		Value: "",
@@ -76,10 +82,20 @@ var analyzeFlags = []cli.Flag{
 		Hidden: true,
 		Value:  0,
 	},
+	cli.StringFlag{
+		Name:  "analyze.sizes",
+		Value: "",
+		Usage: "Split requests of different sizes at the buckets of this size distribution. Same format as obj.sizes.",
+	},
 	cli.BoolFlag{
 		Name:  "analyze.v",
 		Usage: "Display additional analysis data.",
 	},
+	cli.StringFlag{
+		Name:  "slo",
+		Value: "",
+		Usage: "Latency objectives, for example 'GET:100ms,PUT:50ms+20ms/MiB,*:1s'. Replaces objectives stored in benchmark data when analyzing.",
+	},
 	cli.StringFlag{
 		Name:   serverFlagName,
 		Usage:  "When running benchmarks open a webserver on this ip:port and keep it running afterwards.",
@@ -171,6 +187,9 @@ func printMixedOpAnalysis(ctx *cli.Context, aggr aggregate.Aggregated, details b
 
 		if ops.Skipped {
 			console.Println("Skipping", ops.Type, "too few samples. Longer benchmark run required for reliable results.")
+			// Consistency results are reported even if there are few of them.
+			printVerify(ops)
+			printVisibility(ops.Visibility, details)
 			continue
 		}
 
@@ -184,6 +203,12 @@ func printMixedOpAnalysis(ctx *cli.Context, aggr aggregate.Aggregated, details b
 			}
 			console.SetColor("Print", color.New(color.FgWhite))
 		}
+		printVerify(ops)
+		printVisibility(ops.Visibility, details)
+		printSLO(ops.SLO, details)
+		if bench.IsDelayOp(ops.Type) {
+			continue
+		}
 		eps := ops.ThroughputByHost
 		if len(eps) == 1 || !details {
 			console.Println(" * Throughput:", ops.Throughput.StringDetails(details))
@@ -204,7 +229,10 @@ func printMixedOpAnalysis(ctx *cli.Context, aggr aggregate.Aggregated, details b
 			}
 		}
 
+		printBuckets(ops)
+
 		if details {
+			printPrefixes(ops)
 			printRequestAnalysis(ctx, ops, details)
 			console.SetColor("Print", color.New(color.FgWhite))
 		}
@@ -226,7 +254,21 @@ func printMixedOpAnalysis(ctx *cli.Context, aggr aggregate.Aggregated, details b
 }
 
 func printAnalysis(ctx *cli.Context, o bench.Operations) {
+	if wantPhase := ctx.String("analyze.phase"); wantPhase != "" {
+		o = o.FilterByPhase(wantPhase)
+	} else if phases := o.Phases(); len(phases) > 1 {
+		for _, phase := range phases {
+			if !globalJSON {
+				console.SetColor("Print", color.New(color.FgHiWhite))
+				console.Println("\n========================================")
+				console.Println("Phase:", phase)
+			}
+			printAnalysis(ctx, o.FilterByPhase(phase))
+		}
+		return
+	}
 	details := ctx.Bool("analyze.v")
+	o.CheckSLOs(newSLOs(ctx))
 	var wrSegs io.Writer
 	prefiltered := false
 	if fn := ctx.String("analyze.out"); fn != "" {
@@ -246,7 +288,7 @@ func printAnalysis(ctx *cli.Context, o bench.Operations) {
 			hosts := o.Endpoints()
 			console.Println("Host not found, valid hosts are:")
 			for _, h := range hosts {
-				console.Println("\t* %s", h)
+				console.Printf("\t* %s\n", h)
 			}
 			return
 		}
@@ -258,6 +300,17 @@ func printAnalysis(ctx *cli.Context, o bench.Operations) {
 		prefiltered = prefiltered || o.IsMixed()
 		o = o.FilterByOp(wantOp)
 	}
+	if len(o.Steps()) > 1 {
+		sweep := aggregate.SweepAnalysis(o)
+		if globalJSON {
+			b, err := json.MarshalIndent(sweep, "", "  ")
+			fatalIf(probe.NewError(err), "Unable to marshal data.")
+			os.Stdout.Write(b)
+			return
+		}
+		printSweep(sweep)
+		return
+	}
 	durFn := func(total time.Duration) time.Duration {
 		if total <= 0 {
 			return 0
@@ -268,6 +321,7 @@ func printAnalysis(ctx *cli.Context, o bench.Operations) {
 		Prefiltered: prefiltered,
 		DurFunc:     durFn,
 		SkipDur:     ctx.Duration("analyze.skip"),
+		SizeBounds:  analysisSizeBounds(ctx),
 	})
 	if wrSegs != nil {
 		for _, ops := range aggr.Operations {
@@ -328,6 +382,12 @@ func printAnalysis(ctx *cli.Context, o bench.Operations) {
 				console.Println("")
 			}
 		}
+		printVerify(ops)
+		printSLO(ops.SLO, details)
+		if bench.IsDelayOp(typ) {
+			printVisibility(ops.Visibility, details)
+			continue
+		}
 
 		if ops.Skipped {
 			console.SetColor("Print", color.New(color.FgHiWhite))
@@ -374,6 +434,10 @@ func printAnalysis(ctx *cli.Context, o bench.Operations) {
 				}
 			}
 		}
+		printBuckets(ops)
+		if details {
+			printPrefixes(ops)
+		}
 		segs := ops.Throughput.Segmented
 		dur := time.Millisecond * time.Duration(segs.SegmentDurationMillis)
 		console.SetColor("Print", color.New(color.FgHiWhite))
@@ -385,6 +449,171 @@ func printAnalysis(ctx *cli.Context, o bench.Operations) {
 	}
 }
 
+// printBuckets prints the throughput of each bucket, if operations used several buckets.
+func printBuckets(ops aggregate.Operation) {
+	if len(ops.ThroughputByBucket) == 0 {
+		return
+	}
+	buckets := make([]string, 0, len(ops.ThroughputByBucket))
+	for bucket := range ops.ThroughputByBucket {
+		buckets = append(buckets, bucket)
+	}
+	sort.Strings(buckets)
+	console.SetColor("Print", color.New(color.FgHiWhite))
+	console.Println("\nThroughput by bucket:")
+	for _, bucket := range buckets {
+		t := ops.ThroughputByBucket[bucket]
+		console.SetColor("Print", color.New(color.FgWhite))
+		console.Print(" * ", bucket, ": Avg: ", t.StringDetails(false), ".")
+		if t.Errors > 0 {
+			console.SetColor("Print", color.New(color.FgHiRed))
+			console.Print(" Errors: ", t.Errors)
+		}
+		console.Println("")
+	}
+	console.SetColor("Print", color.New(color.FgWhite))
+}
+
+// printPrefixes prints statistics of the busiest prefixes, if operations used several prefixes.
+func printPrefixes(ops aggregate.Operation) {
+	const maxPrefixes = 10
+	if len(ops.ByPrefix) == 0 {
+		return
+	}
+	prefixes := make([]string, 0, len(ops.ByPrefix))
+	for prefix := range ops.ByPrefix {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Slice(prefixes, func(i, j int) bool {
+		a, b := ops.ByPrefix[prefixes[i]], ops.ByPrefix[prefixes[j]]
+		if a.Throughput.Operations != b.Throughput.Operations {
+			return a.Throughput.Operations > b.Throughput.Operations
+		}
+		return prefixes[i] < prefixes[j]
+	})
+	printPrefix := func(prefix string) {
+		p := ops.ByPrefix[prefix]
+		if prefix == "" {
+			prefix = "(none)"
+		}
+		console.SetColor("Print", color.New(color.FgWhite))
+		console.Print(" * ", prefix, ": Avg: ", p.Throughput.StringDetails(false), ", ", p.Throughput.Operations, " ops.")
+		console.Printf(" Latency avg: %dms, 50%%: %dms, 90%%: %dms, 99%%: %dms, slowest: %dms.", p.DurAvgMillis, p.DurMedianMillis, p.Dur90Millis, p.Dur99Millis, p.SlowestMillis)
+		if p.Throughput.Errors > 0 {
+			console.SetColor("Print", color.New(color.FgHiRed))
+			console.Print(" Errors: ", p.Throughput.Errors)
+		}
+		console.Println("")
+	}
+	console.SetColor("Print", color.New(color.FgHiWhite))
+	console.Printf("\nRequests by prefix, %d prefixes:\n", len(prefixes))
+	if len(prefixes) <= maxPrefixes+1 {
+		for _, prefix := range prefixes {
+			printPrefix(prefix)
+		}
+		console.SetColor("Print", color.New(color.FgWhite))
+		return
+	}
+	for _, prefix := range prefixes[:maxPrefixes-1] {
+		printPrefix(prefix)
+	}
+	console.SetColor("Print", color.New(color.FgWhite))
+	console.Printf(" * (%d prefixes not shown)\n", len(prefixes)-maxPrefixes)
+	printPrefix(prefixes[len(prefixes)-1])
+	console.SetColor("Print", color.New(color.FgWhite))
+}
+
+// printVerify prints the number of reads that failed verification, if any.
+func printVerify(ops aggregate.Operation) {
+	if ops.CorruptReads == 0 && ops.StaleReads == 0 && ops.MissingReads == 0 {
+		return
+	}
+	console.SetColor("Print", color.New(color.FgHiRed))
+	if ops.MissingReads > 0 {
+		console.Printf("Verification failed: %d corrupt, %d stale, %d missing reads.\n", ops.CorruptReads, ops.StaleReads, ops.MissingReads)
+	} else {
+		console.Printf("Verification failed: %d corrupt, %d stale reads.\n", ops.CorruptReads, ops.StaleReads)
+	}
+	console.SetColor("Print", color.New(color.FgWhite))
+}
+
+// printVisibility prints the delay before writes were observed, if measured.
+// With details the delay is printed over time.
+func printVisibility(v *aggregate.Visibility, details bool) {
+	if v == nil {
+		return
+	}
+	console.Println(" * Delay:", visibilityDelayString(v.VisibilityDelay))
+	if !details || len(v.Segments) < 2 {
+		return
+	}
+	console.Println("\nDelay over time:")
+	for _, seg := range v.Segments {
+		console.Println(" * "+seg.Start.Format("15:04:05")+":", visibilityDelayString(seg.VisibilityDelay))
+	}
+}
+
+func visibilityDelayString(d aggregate.VisibilityDelay) string {
+	if d.Delayed == 0 {
+		return fmt.Sprintf("No writes observed. Timed out: %d.", d.TimedOut)
+	}
+	s := fmt.Sprintf("Avg: %v, 50%%: %v, 90%%: %v, 99%%: %v, Slowest: %v.",
+		time.Duration(d.DelayAvgMillis)*time.Millisecond,
+		time.Duration(d.DelayMedianMillis)*time.Millisecond,
+		time.Duration(d.Delay90Millis)*time.Millisecond,
+		time.Duration(d.Delay99Millis)*time.Millisecond,
+		time.Duration(d.DelayMaxMillis)*time.Millisecond)
+	if d.TimedOut > 0 {
+		s += fmt.Sprintf(" Timed out: %d.", d.TimedOut)
+	}
+	return s
+}
+
+// printSLO prints latency objective statistics, if any.
+func printSLO(slo *aggregate.SLO, details bool) {
+	if slo == nil {
+		return
+	}
+	console.SetColor("Print", color.New(color.FgWhite))
+	if slo.Violated > 0 {
+		console.SetColor("Print", color.New(color.FgHiRed))
+	}
+	console.Printf(" * SLO: %.2f%% of %d requests violated the objective.", 100*slo.ViolationRatio(), slo.Checked)
+	var worst *aggregate.SLOSegment
+	for i := range slo.Segments {
+		seg := &slo.Segments[i]
+		if worst == nil || seg.ViolationRatio() > worst.ViolationRatio() {
+			worst = seg
+		}
+	}
+	if worst != nil && len(slo.Segments) > 1 {
+		dur := time.Duration(slo.SegmentDurationMillis) * time.Millisecond
+		console.Printf(" Worst %v: %.2f%% starting %v.", dur, 100*worst.ViolationRatio(), worst.Start.Round(time.Millisecond))
+	}
+	console.Println("")
+	console.SetColor("Print", color.New(color.FgWhite))
+	if !details {
+		return
+	}
+	if len(slo.ByHost) > 1 {
+		hosts := make([]string, 0, len(slo.ByHost))
+		for ep := range slo.ByHost {
+			hosts = append(hosts, ep)
+		}
+		sort.Strings(hosts)
+		for _, ep := range hosts {
+			h := slo.ByHost[ep]
+			console.Printf("   - %s: %.2f%% of %d violated.\n", ep, 100*h.ViolationRatio(), h.Checked)
+		}
+	}
+	if len(slo.Segments) > 1 {
+		console.Println("   SLO violations by time:")
+		for _, seg := range slo.Segments {
+			console.Printf("   - %v: %.2f%% of %d violated.\n", seg.Start.Round(time.Millisecond), 100*seg.ViolationRatio(), seg.Checked)
+		}
+	}
+}
+
 func writeSegs(ctx *cli.Context, wrSegs io.Writer, ops bench.Operations, allThreads, details bool) {
 	if wrSegs == nil {
 		return
@@ -589,9 +818,33 @@ func analysisDur(ctx *cli.Context, total time.Duration) time.Duration {
 	return d
 }
 
+// analysisSizeBounds returns the size boundaries to split requests at.
+// The declared object size distribution is used, unless one is given for analysis.
+func analysisSizeBounds(ctx *cli.Context) []int64 {
+	spec := ctx.String("analyze.sizes")
+	if spec == "" {
+		spec = ctx.String("obj.sizes")
+	}
+	if spec == "" {
+		return nil
+	}
+	sizes, err := parseSizeDistribution(spec)
+	fatalIf(probe.NewError(err), "Invalid size distribution")
+	return sizes.Bounds()
+}
+
+// newSLOs returns the latency objectives specified by the context.
+func newSLOs(ctx *cli.Context) bench.SLOs {
+	slos, err := bench.ParseSLOs(ctx.String("slo"))
+	fatalIf(probe.NewError(err), "Invalid -slo value")
+	return slos
+}
+
 func checkAnalyze(ctx *cli.Context) {
 	if analysisDur(ctx, time.Minute) == 0 {
 		err := errors.New("-analyze.dur cannot be 0")
 		fatal(probe.NewError(err), "Invalid -analyze.dur value")
 	}
+	newSLOs(ctx)
+	analysisSizeBounds(ctx)
 }
@@ -33,9 +33,11 @@ import (
 	"github.com/klauspost/compress/zstd"
 	"github.com/minio/cli"
 	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7/pkg/tags"
 	"github.com/minio/minio/pkg/console"
 	"github.com/minio/minio/pkg/madmin"
 	"github.com/minio/warp/api"
+	"github.com/minio/warp/pkg/accesslog"
 	"github.com/minio/warp/pkg/bench"
 )
 
@@ -69,6 +71,14 @@ var benchFlags = []cli.Flag{
 		Usage: "The percentage the last 6/25 time blocks must be within current speed to auto terminate.",
 		Value: 7.5,
 	},
+	cli.Float64Flag{
+		Name:  "rps",
+		Usage: "Start operations at a fixed rate per second instead of as fast as possible. Latency is measured from the scheduled start.",
+	},
+	cli.BoolFlag{
+		Name:  "rps.poisson",
+		Usage: "Use exponentially distributed intervals between operations when --rps is set.",
+	},
 	cli.BoolFlag{
 		Name:  "noclear",
 		Usage: "Do not clear bucket before or after running benchmarks. Use when running multiple clients.",
@@ -100,6 +110,9 @@ func runBench(ctx *cli.Context, b bench.Benchmark) error {
 	if ab != nil {
 		return runClientBenchmark(ctx, b, ab)
 	}
+	if activeScenario != nil {
+		return activeScenario.runPhase(ctx, b)
+	}
 	if done, err := runServerBenchmark(ctx); done || err != nil {
 		fatalIf(probe.NewError(err), "Error running remote benchmark")
 		return nil
@@ -112,6 +125,8 @@ func runBench(ctx *cli.Context, b bench.Benchmark) error {
 	monitor.InfoLn("Preparing server.")
 	pgDone := make(chan struct{})
 	c := b.GetCommon()
+	setupCommon(ctx, c, newAccessLog(ctx))
+	bctx, stopSignal := cancelOnSignal(context.Background(), c)
 	c.Clear = !ctx.Bool("noclear")
 	if ctx.Bool("autoterm") {
 		// TODO: autoterm cannot be used when in client/server mode
@@ -159,12 +174,23 @@ func runBench(ctx *cli.Context, b bench.Benchmark) error {
 		close(pgDone)
 	}
 
-	err := b.Prepare(context.Background())
-	fatalIf(probe.NewError(err), "Error preparing server")
+	err := b.Prepare(bctx)
 	if c.PrepareProgress != nil {
 		close(c.PrepareProgress)
 		<-pgDone
 	}
+	if err != nil && bctx.Err() != nil {
+		stopSignal()
+		if err := c.CloseAccessLog(); err != nil {
+			monitor.Errorln("Error writing access log:", err)
+		}
+		if !ctx.Bool("keep-data") && !ctx.Bool("noclear") {
+			monitor.InfoLn("Starting cleanup...")
+			b.Cleanup(context.Background())
+		}
+		return errInterrupted
+	}
+	fatalIf(probe.NewError(err), "Error preparing server")
 
 	// Start after waiting a second or until we reached the start time.
 	tStart := time.Now().Add(time.Second * 3)
@@ -180,62 +206,49 @@ func runBench(ctx *cli.Context, b bench.Benchmark) error {
 	}
 
 	benchDur := ctx.Duration("duration")
-	ctx2, cancel := context.WithDeadline(context.Background(), tStart.Add(benchDur))
-	defer cancel()
-	start := make(chan struct{})
-	go func() {
-		<-time.After(time.Until(tStart))
-		monitor.InfoLn("Benchmark starting...")
-		close(start)
-	}()
-
 	fileName := ctx.String("benchdata")
 	cID := pRandASCII(4)
 	if fileName == "" {
 		fileName = fmt.Sprintf("%s-%s-%s-%s", appName, ctx.Command.Name, time.Now().Format("2006-01-02[150405]"), cID)
 	}
 
-	prof, err := startProfiling(ctx2, ctx)
+	prof, err := startProfiling(context.Background(), ctx)
 	fatalIf(probe.NewError(err), "Unable to start profile.")
 	monitor.InfoLn("Starting benchmark in ", time.Until(tStart).Round(time.Second), "...")
-	pgDone = make(chan struct{})
-	if !globalQuiet && !globalJSON {
-		pg := newProgressBar(int64(benchDur), pb.U_DURATION)
-		go func() {
-			defer close(pgDone)
-			defer pg.Finish()
-			pg.SetCaption("Benchmarking:")
-			tick := time.Tick(time.Millisecond * 125)
-			done := ctx2.Done()
-			for {
-				select {
-				case t := <-tick:
-					elapsed := t.Sub(tStart)
-					if elapsed < 0 {
-						continue
-					}
-					pg.Set64(int64(elapsed))
-					pg.Update()
-					monitor.InfoQuietln(fmt.Sprintf("Running benchmark: %0.0f%%...", 100*float64(elapsed)/float64(benchDur)))
-				case <-done:
-					pg.Set64(int64(benchDur))
-					pg.Update()
-					return
-				}
+	var ops bench.Operations
+	if steps := sweepSteps(ctx); len(steps) > 0 {
+		for i, step := range steps {
+			if bctx.Err() != nil {
+				break
 			}
-		}()
+			if step.concurrency > 0 {
+				c.Concurrency = step.concurrency
+			}
+			if step.rps > 0 {
+				c.Rate = bench.NewRateLimiter(step.rps, ctx.Bool("rps.poisson"))
+			}
+			if i > 0 {
+				tStart = time.Now()
+			}
+			monitor.InfoLn(fmt.Sprintf("Sweep step %d/%d: %s", i+1, len(steps), step.name))
+			stepOps := runStep(bctx, monitor, b, tStart, benchDur, fmt.Sprintf("Step %d/%d:", i+1, len(steps)))
+			stepOps.SetStep(step.name, tStart)
+			ops = append(ops, stepOps...)
+		}
 	} else {
-		close(pgDone)
+		ops = runStep(bctx, monitor, b, tStart, benchDur, "Benchmarking:")
+	}
+	interrupted := bctx.Err() != nil
+	stopSignal()
+	if err := c.CloseAccessLog(); err != nil {
+		monitor.Errorln("Error writing access log:", err)
 	}
-	ops, _ := b.Start(ctx2, start)
-	cancel()
-	<-pgDone
 
 	// Previous context is canceled, create a new...
 	monitor.InfoLn("Saving benchmark data...")
-	ctx2 = context.Background()
+	ctx2 := context.Background()
 	ops.SortByStartTime()
-	ops.SetClientID(cID)
+	finishOps(ops, c, cID)
 	prof.stop(ctx2, ctx, fileName+".profiles.zip")
 
 	f, err := os.Create(fileName + ".csv.zst")
@@ -261,9 +274,77 @@ func runBench(ctx *cli.Context, b bench.Benchmark) error {
 		b.Cleanup(context.Background())
 	}
 	monitor.InfoLn("Cleanup Done.")
+	if interrupted {
+		return errInterrupted
+	}
 	return nil
 }
 
+// setupCommon sets the options shared by all benchmarks from the context
+// and the access log the requests are written to.
+func setupCommon(ctx *cli.Context, c *bench.Common, log *accesslog.Writer) {
+	c.AccessLog = log
+	c.SLOs = newSLOs(ctx)
+	c.Bucket, c.BucketSet = newBucketSet(ctx)
+	c.Rate = newRateLimiter(ctx)
+	c.KeyDist = newKeyDist(ctx)
+}
+
+// finishOps sets the client ID and buckets of the operations
+// and checks them against the SLOs of the benchmark.
+func finishOps(ops bench.Operations, c *bench.Common, clientID string) {
+	ops.SetClientID(clientID)
+	ops.SetBuckets(c.BucketSet)
+	ops.CheckSLOs(c.SLOs)
+}
+
+// runStep runs the benchmark from tStart for the specified duration
+// and shows the progress with the specified caption.
+func runStep(ctx context.Context, monitor *api.Server, b bench.Benchmark, tStart time.Time, benchDur time.Duration, caption string) bench.Operations {
+	ctx2, cancel := context.WithDeadline(ctx, tStart.Add(benchDur))
+	defer cancel()
+	start := make(chan struct{})
+	go func() {
+		<-time.After(time.Until(tStart))
+		monitor.InfoLn("Benchmark starting...")
+		close(start)
+	}()
+
+	pgDone := make(chan struct{})
+	if !globalQuiet && !globalJSON {
+		pg := newProgressBar(int64(benchDur), pb.U_DURATION)
+		go func() {
+			defer close(pgDone)
+			defer pg.Finish()
+			pg.SetCaption(caption)
+			tick := time.Tick(time.Millisecond * 125)
+			done := ctx2.Done()
+			for {
+				select {
+				case t := <-tick:
+					elapsed := t.Sub(tStart)
+					if elapsed < 0 {
+						continue
+					}
+					pg.Set64(int64(elapsed))
+					pg.Update()
+					monitor.InfoQuietln(fmt.Sprintf("Running benchmark: %0.0f%%...", 100*float64(elapsed)/float64(benchDur)))
+				case <-done:
+					pg.Set64(int64(benchDur))
+					pg.Update()
+					return
+				}
+			}
+		}()
+	} else {
+		close(pgDone)
+	}
+	ops, _ := b.Start(ctx2, start)
+	cancel()
+	<-pgDone
+	return ops
+}
+
 var activeBenchmarkMu sync.Mutex
 var activeBenchmark *clientBenchmark
 
@@ -362,6 +443,9 @@ func runClientBenchmark(ctx *cli.Context, b bench.Benchmark, cb *clientBenchmark
 	ctx2, cancel := context.WithCancel(cb.ctx)
 	defer cancel()
 	cb.Unlock()
+	setupCommon(ctx, b.GetCommon(), newAccessLog(ctx))
+	ctx2, stopSignal := cancelOnSignal(ctx2, b.GetCommon())
+	defer stopSignal()
 	err = b.Prepare(ctx2)
 	cb.stageDone(stagePrepare, err)
 	if err != nil {
@@ -399,6 +483,10 @@ func runClientBenchmark(ctx *cli.Context, b bench.Benchmark, cb *clientBenchmark
 	}
 
 	ops, err := b.Start(ctx2, start)
+	if err := b.GetCommon().CloseAccessLog(); err != nil {
+		console.Errorln("Error writing access log:", err)
+	}
+	finishOps(ops, b.GetCommon(), cID)
 	cb.Lock()
 	cb.results = ops
 	cb.Unlock()
@@ -406,7 +494,6 @@ func runClientBenchmark(ctx *cli.Context, b bench.Benchmark, cb *clientBenchmark
 	if err != nil {
 		return err
 	}
-	ops.SetClientID(cID)
 	ops.SortByStartTime()
 
 	f, err := os.Create(fileName + ".csv.zst")
@@ -528,6 +615,70 @@ func checkBenchmark(ctx *cli.Context) {
 			fatalIf(errDummy(), "autoterm.pct cannot be zero or negative")
 		}
 	}
+	if opts := putOpts(ctx); len(opts.UserTags) > 0 {
+		_, err := tags.MapToObjectTags(opts.UserTags)
+		fatalIf(probe.NewError(err), "Invalid --tags or --tag.size")
+	}
+	if ctx.Int("metadata") < 0 || ctx.Int("metadata.size") < 0 {
+		fatalIf(errDummy(), "metadata and metadata.size cannot be negative")
+	}
+	newBucketSet(ctx)
+	if ctx.Float64("rps") < 0 {
+		fatalIf(errDummy(), "rps cannot be negative")
+	}
+	if ctx.Bool("rps.poisson") && ctx.Float64("rps") == 0 && ctx.String("steps.rps") == "" {
+		fatalIf(errDummy(), "rps.poisson requires --rps")
+	}
+	sweepSteps(ctx)
+	newKeyDist(ctx)
+}
+
+// newKeyDist returns the key distribution specified by the context.
+// nil is returned for uniform selection.
+func newKeyDist(ctx *cli.Context) *bench.KeyDist {
+	kind := ctx.String("key.dist")
+	if kind == "" || kind == bench.KeyDistUniform {
+		return nil
+	}
+	k, err := bench.NewKeyDist(kind, ctx.Float64("key.skew"), ctx.Float64("key.hot")/100, ctx.Float64("key.hot.requests")/100)
+	fatalIf(probe.NewError(err), "Invalid key distribution")
+	return k
+}
+
+// newRateLimiter returns the rate limiter specified by the context, or nil if none.
+func newRateLimiter(ctx *cli.Context) *bench.RateLimiter {
+	rps := ctx.Float64("rps")
+	if rps <= 0 {
+		return nil
+	}
+	return bench.NewRateLimiter(rps, ctx.Bool("rps.poisson"))
+}
+
+// newBucketSet returns the bucket to use and the set of buckets objects are spread across, if any.
+// The returned bucket is the first bucket of the set.
+func newBucketSet(ctx *cli.Context) (string, *bench.BucketSet) {
+	bucket := ctx.String("bucket")
+	var names []string
+	switch n := ctx.Int("buckets"); {
+	case strings.Contains(bucket, ","):
+		if n > 1 {
+			fatalIf(errDummy(), "--buckets cannot be used with a list of buckets")
+		}
+		for _, name := range strings.Split(bucket, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	case n > 1:
+		for i := 0; i < n; i++ {
+			names = append(names, fmt.Sprintf("%s-%d", bucket, i))
+		}
+	default:
+		return bucket, nil
+	}
+	set, err := bench.NewBucketSet(names, ctx.Float64("bucket.skew"))
+	fatalIf(probe.NewError(err), "Invalid bucket selection")
+	return names[0], set
 }
 
 // time format for start time.
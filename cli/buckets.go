@@ -0,0 +1,84 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"github.com/minio/cli"
+	"github.com/minio/minio/pkg/console"
+	"github.com/minio/warp/pkg/bench"
+)
+
+var (
+	bucketsFlags = []cli.Flag{
+		cli.IntFlag{
+			Name:  "count",
+			Value: 1000,
+			Usage: "Number of buckets to keep. When reached the oldest bucket is removed before creating a new one.",
+		},
+		cli.BoolFlag{
+			Name:  "noconfig",
+			Usage: "Do not set versioning, tagging and policy on created buckets.",
+		},
+	}
+)
+
+var bucketsCmd = cli.Command{
+	Name:   "buckets",
+	Usage:  "benchmark bucket creation, configuration and removal",
+	Action: mainBuckets,
+	Before: setGlobalsFromContext,
+	Flags:  combineFlags(globalFlags, ioFlags, bucketsFlags, benchFlags, analyzeFlags),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS]
+  -> see https://github.com/minio/warp#buckets
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}`,
+}
+
+// mainBuckets is the entry point for buckets command.
+func mainBuckets(ctx *cli.Context) error {
+	checkBucketsSyntax(ctx)
+	b := bench.Buckets{
+		Common: bench.Common{
+			Client:      newClient(ctx),
+			Concurrency: ctx.Int("concurrent"),
+			Bucket:      ctx.String("bucket"),
+			Location:    "",
+		},
+		Buckets: ctx.Int("count"),
+		Config:  !ctx.Bool("noconfig"),
+	}
+	return runBench(ctx, &b)
+}
+
+func checkBucketsSyntax(ctx *cli.Context) {
+	if ctx.NArg() > 0 {
+		console.Fatal("Command takes no arguments")
+	}
+	if ctx.Int("count") < ctx.Int("concurrent") {
+		console.Fatal("--count must be at least --concurrent")
+	}
+
+	checkAnalyze(ctx)
+	checkBenchmark(ctx)
+}
@@ -96,11 +96,24 @@ func init() {
 		statCmd,
 		selectCmd,
 		versionedCmd,
+		multipartCmd,
+		copyCmd,
+		taggingCmd,
+		bucketsCmd,
+		consistencyCmd,
+		replicateCmd,
+		replayCmd,
+		modeledCmd,
 	}
+	sweepCmd.Subcommands = sweepCommands(a)
 	b := []cli.Command{
+		sweepCmd,
+		runCmd,
 		analyzeCmd,
 		cmpCmd,
 		mergeCmd,
+		importCmd,
+		modelCmd,
 		clientCmd,
 	}
 	appCmds = append(a, b...)
@@ -51,12 +51,18 @@ const (
 )
 
 func newClient(ctx *cli.Context) func() (cl *minio.Client, done func()) {
-	hosts := parseHosts(ctx.String("host"))
+	return newHostsClient(ctx, "", parseHosts(ctx.String("host")))
+}
+
+// newHostsClient returns a function that selects a client for one of the hosts
+// using the host selection and client options set in the context.
+// Credentials, TLS and region are read from flags with the specified prefix.
+func newHostsClient(ctx *cli.Context, prefix string, hosts []string) func() (cl *minio.Client, done func()) {
 	switch len(hosts) {
 	case 0:
 		fatalIf(probe.NewError(errors.New("no host defined")), "Unable to create MinIO client")
 	case 1:
-		cl, err := getClient(ctx, hosts[0])
+		cl, err := getClient(ctx, prefix, hosts[0])
 		fatalIf(probe.NewError(err), "Unable to create MinIO client")
 
 		return func() (*minio.Client, func()) {
@@ -69,7 +75,7 @@ func newClient(ctx *cli.Context) func() (cl *minio.Client, done func()) {
 		var mu sync.Mutex
 		clients := make([]*minio.Client, len(hosts))
 		for i := range hosts {
-			cl, err := getClient(ctx, hosts[i])
+			cl, err := getClient(ctx, prefix, hosts[i])
 			fatalIf(probe.NewError(err), "Unable to create MinIO client")
 			clients[i] = cl
 		}
@@ -85,7 +91,7 @@ func newClient(ctx *cli.Context) func() (cl *minio.Client, done func()) {
 		var mu sync.Mutex
 		clients := make([]*minio.Client, len(hosts))
 		for i := range hosts {
-			cl, err := getClient(ctx, hosts[i])
+			cl, err := getClient(ctx, prefix, hosts[i])
 			fatalIf(probe.NewError(err), "Unable to create MinIO client")
 			clients[i] = cl
 		}
@@ -102,7 +108,7 @@ func newClient(ctx *cli.Context) func() (cl *minio.Client, done func()) {
 		var mu sync.Mutex
 		clients := make([]*minio.Client, len(hosts))
 		for i := range hosts {
-			cl, err := getClient(ctx, hosts[i])
+			cl, err := getClient(ctx, prefix, hosts[i])
 			fatalIf(probe.NewError(err), "Unable to create MinIO client")
 			clients[i] = cl
 		}
@@ -159,26 +165,27 @@ func newClient(ctx *cli.Context) func() (cl *minio.Client, done func()) {
 }
 
 // getClient creates a client with the specified host and the options set in the context.
-func getClient(ctx *cli.Context, host string) (*minio.Client, error) {
+// Credentials, TLS and region are read from flags with the specified prefix.
+func getClient(ctx *cli.Context, prefix, host string) (*minio.Client, error) {
 	var creds *credentials.Credentials
 	switch strings.ToUpper(ctx.String("signature")) {
 	case "S3V4":
 		// if Signature version '4' use NewV4 directly.
-		creds = credentials.NewStaticV4(ctx.String("access-key"), ctx.String("secret-key"), "")
+		creds = credentials.NewStaticV4(ctx.String(prefix+"access-key"), ctx.String(prefix+"secret-key"), "")
 	case "S3V2":
 		// if Signature version '2' use NewV2 directly.
-		creds = credentials.NewStaticV2(ctx.String("access-key"), ctx.String("secret-key"), "")
+		creds = credentials.NewStaticV2(ctx.String(prefix+"access-key"), ctx.String(prefix+"secret-key"), "")
 	default:
 		fatal(probe.NewError(errors.New("unknown signature method. S3V2 and S3V4 is available")), strings.ToUpper(ctx.String("signature")))
 	}
 
 	cl, err := minio.New(host, &minio.Options{
 		Creds:        creds,
-		Secure:       ctx.Bool("tls"),
-		Region:       ctx.String("region"),
+		Secure:       ctx.Bool(prefix + "tls"),
+		Region:       ctx.String(prefix + "region"),
 		BucketLookup: minio.BucketLookupAuto,
 		CustomMD5:    md5simd.NewServer().NewHash,
-		Transport:    clientTransport(ctx),
+		Transport:    clientTransport(ctx, prefix),
 	})
 	if err != nil {
 		return nil, err
@@ -187,7 +194,7 @@ func getClient(ctx *cli.Context, host string) (*minio.Client, error) {
 	return cl, nil
 }
 
-func clientTransport(ctx *cli.Context) http.RoundTripper {
+func clientTransport(ctx *cli.Context, prefix string) http.RoundTripper {
 	tr := &http.Transport{
 		Proxy: http.ProxyFromEnvironment,
 		DialContext: (&net.Dialer{
@@ -207,7 +214,7 @@ func clientTransport(ctx *cli.Context) http.RoundTripper {
 		//    https://golang.org/src/net/http/transport.go?h=roundTrip#L1843
 		DisableCompression: true,
 	}
-	if ctx.Bool("tls") {
+	if ctx.Bool(prefix + "tls") {
 		// Keep TLS config.
 		tlsConfig := &tls.Config{
 			RootCAs: mustGetSystemCertPool(),
@@ -266,7 +273,7 @@ func newAdminClient(ctx *cli.Context) *madmin.AdminClient {
 	}
 	cl, err := madmin.New(hosts[0], ctx.String("access-key"), ctx.String("secret-key"), ctx.Bool("tls"))
 	fatalIf(probe.NewError(err), "Unable to create MinIO admin client")
-	cl.SetCustomTransport(clientTransport(ctx))
+	cl.SetCustomTransport(clientTransport(ctx, ""))
 	cl.SetAppInfo(appName, pkg.Version)
 	return cl
 }
@@ -0,0 +1,128 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio/pkg/console"
+	"github.com/minio/warp/pkg/bench"
+)
+
+var (
+	consistencyFlags = []cli.Flag{
+		cli.IntFlag{
+			Name:  "objects",
+			Value: 1000,
+			Usage: "Number of objects written and checked. Must be at least the concurrency.",
+		},
+		cli.StringFlag{
+			Name:  "obj.size",
+			Value: "10KiB",
+			Usage: "Size of each generated object. Can be a number or 10KiB/MiB/GiB. All sizes are base 2 binary.",
+		},
+		cli.Float64Flag{
+			Name:  "put-distrib",
+			Usage: "The amount of PUT (overwrite) operations.",
+			Value: 75,
+		},
+		cli.Float64Flag{
+			Name:  "delete-distrib",
+			Usage: "The amount of DELETE operations.",
+			Value: 25,
+		},
+		cli.StringFlag{
+			Name:  "read-host",
+			Usage: "Host(s) to send GET, HEAD and LIST checks to. Uses --host if not set. Multiple hosts can be specified as a comma separated list.",
+		},
+		cli.DurationFlag{
+			Name:  "visibility.timeout",
+			Value: 10 * time.Second,
+			Usage: "Maximum time to wait for a write to become visible.",
+		},
+	}
+)
+
+var consistencyCmd = cli.Command{
+	Name:   "consistency",
+	Usage:  "check read-after-write and list-after-write consistency",
+	Action: mainConsistency,
+	Before: setGlobalsFromContext,
+	Flags:  combineFlags(globalFlags, ioFlags, consistencyFlags, genFlags, benchFlags, analyzeFlags),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+  Objects are uploaded, overwritten and deleted.
+  After each write the object is read with GET, HEAD and LIST, which must observe the write.
+  Checks that do not are repeated until the write is visible or --visibility.timeout is reached.
+
+USAGE:
+  {{.HelpName}} [FLAGS]
+  -> see https://github.com/minio/warp#consistency
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}`,
+}
+
+// mainConsistency is the entry point for consistency command.
+func mainConsistency(ctx *cli.Context) error {
+	checkConsistencySyntax(ctx)
+	src := newGenSource(ctx)
+	sse := newSSE(ctx)
+	b := bench.Consistency{
+		Common: bench.Common{
+			Client:      newClient(ctx),
+			Concurrency: ctx.Int("concurrent"),
+			Source:      src,
+			Bucket:      ctx.String("bucket"),
+			Location:    "",
+			PutOpts:     putOpts(ctx),
+		},
+		Objects:           ctx.Int("objects"),
+		DeleteRatio:       ctx.Float64("delete-distrib") / (ctx.Float64("put-distrib") + ctx.Float64("delete-distrib")),
+		VisibilityTimeout: ctx.Duration("visibility.timeout"),
+		GetOpts:           minio.GetObjectOptions{ServerSideEncryption: sse},
+		StatOpts:          minio.StatObjectOptions{ServerSideEncryption: sse},
+	}
+	if hosts := ctx.String("read-host"); hosts != "" {
+		b.ReadClient = newHostsClient(ctx, "", parseHosts(hosts))
+	}
+	return runBench(ctx, &b)
+}
+
+func checkConsistencySyntax(ctx *cli.Context) {
+	if ctx.NArg() > 0 {
+		console.Fatal("Command takes no arguments")
+	}
+	if ctx.Int("objects") < ctx.Int("concurrent") {
+		console.Fatal("objects must be at least the concurrency")
+	}
+	put, del := ctx.Float64("put-distrib"), ctx.Float64("delete-distrib")
+	if put < 0 || del < 0 || put+del <= 0 {
+		console.Fatal("put-distrib and delete-distrib must be >= 0 with a positive sum")
+	}
+	if ctx.Duration("visibility.timeout") <= 0 {
+		console.Fatal("visibility.timeout must be > 0")
+	}
+
+	checkAnalyze(ctx)
+	checkBenchmark(ctx)
+}
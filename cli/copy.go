@@ -0,0 +1,108 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"github.com/minio/cli"
+	"github.com/minio/minio/pkg/console"
+	"github.com/minio/warp/pkg/bench"
+)
+
+var (
+	copyFlags = []cli.Flag{
+		cli.IntFlag{
+			Name:  "objects",
+			Value: 2500,
+			Usage: "Number of objects to upload.",
+		},
+		cli.StringFlag{
+			Name:  "obj.size",
+			Value: "10MiB",
+			Usage: "Size of each generated object. Can be a number or 10KiB/MiB/GiB. All sizes are base 2 binary.",
+		},
+		cli.StringFlag{
+			Name:  "copy.bucket",
+			Value: "",
+			Usage: "Copy objects to this bucket. Objects are copied within --bucket if not set.",
+		},
+		cli.StringFlag{
+			Name:  "part.size",
+			Value: "",
+			Usage: "Copy objects bigger than this using multipart copy with parts of this size.",
+		},
+	}
+)
+
+var copyCmd = cli.Command{
+	Name:   "copy",
+	Usage:  "benchmark server side copy of objects",
+	Action: mainCopy,
+	Before: setGlobalsFromContext,
+	Flags:  combineFlags(globalFlags, ioFlags, copyFlags, keyFlags, genFlags, benchFlags, analyzeFlags),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS]
+  -> see https://github.com/minio/warp#copy
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}`,
+}
+
+// mainCopy is the entry point for copy command.
+func mainCopy(ctx *cli.Context) error {
+	checkCopySyntax(ctx)
+	src := newGenSource(ctx)
+	var partSize uint64
+	if s := ctx.String("part.size"); s != "" {
+		partSize, _ = toSize(s)
+	}
+	b := bench.Copy{
+		Common: bench.Common{
+			Client:      newClient(ctx),
+			Concurrency: ctx.Int("concurrent"),
+			Source:      src,
+			Bucket:      ctx.String("bucket"),
+			Location:    "",
+			PutOpts:     putOpts(ctx),
+		},
+		CreateObjects: ctx.Int("objects"),
+		DestBucket:    ctx.String("copy.bucket"),
+		PartSize:      int64(partSize),
+	}
+	return runBench(ctx, &b)
+}
+
+func checkCopySyntax(ctx *cli.Context) {
+	if ctx.NArg() > 0 {
+		console.Fatal("Command takes no arguments")
+	}
+	if ctx.Int("objects") <= 0 {
+		console.Fatal("There must be more than 0 objects.")
+	}
+	if s := ctx.String("part.size"); s != "" {
+		if _, err := toSize(s); err != nil {
+			console.Fatal("Invalid --part.size: ", s)
+		}
+	}
+
+	checkAnalyze(ctx)
+	checkBenchmark(ctx)
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/minio/cli"
 	"github.com/minio/minio/pkg/console"
+	"github.com/minio/warp/pkg/bench"
 )
 
 // Collection of warp flags currently supported
@@ -133,7 +134,7 @@ func commandLine(ctx *cli.Context) string {
 		}
 		name := flag.GetName()
 		switch name {
-		case "access-key", "secret-key":
+		case "access-key", "secret-key", "remote.access-key", "remote.secret-key":
 			val = "*REDACTED*"
 		}
 		s += " --" + flag.GetName() + "=" + val
@@ -200,6 +201,14 @@ var ioFlags = []cli.Flag{
 		Name:  "noprefix",
 		Usage: "Do not use separate prefix for each thread",
 	},
+	cli.IntFlag{
+		Name:  "prefixes",
+		Usage: "Spread objects over this many prefixes shared by all threads, instead of a prefix for each thread",
+	},
+	cli.Float64Flag{
+		Name:  "prefix.skew",
+		Usage: "Skew of objects over prefixes. 0 is uniform, 1 puts twice as many objects in the first prefix as in the second",
+	},
 	cli.BoolFlag{
 		Name:  "disable-multipart",
 		Usage: "disable multipart uploads",
@@ -213,4 +222,77 @@ var ioFlags = []cli.Flag{
 		Value: "",
 		Usage: "Specify custom storage class, for instance 'STANDARD' or 'REDUCED_REDUNDANCY'.",
 	},
+	cli.IntFlag{
+		Name:  "buckets",
+		Value: 1,
+		Usage: "Spread objects across this many buckets, named after --bucket with a number suffix. Alternatively specify a comma separated list in --bucket.",
+	},
+	cli.Float64Flag{
+		Name:  "bucket.skew",
+		Value: 0,
+		Usage: "Skew of bucket selection when using multiple buckets. 0 is uniform, higher values favor the first buckets.",
+	},
+	cli.IntFlag{
+		Name:  "tags",
+		Value: 0,
+		Usage: "Add this many tags to each upload.",
+	},
+	cli.IntFlag{
+		Name:  "tag.size",
+		Value: 16,
+		Usage: "Size of each tag value added with --tags.",
+	},
+	cli.IntFlag{
+		Name:  "metadata",
+		Value: 0,
+		Usage: "Add this many user metadata headers to each upload.",
+	},
+	cli.IntFlag{
+		Name:  "metadata.size",
+		Value: 32,
+		Usage: "Size of each user metadata value added with --metadata.",
+	},
+	cli.StringFlag{
+		Name:  "logpath",
+		Value: "./test.log",
+		Usage: "Write a JSON line for every request to this file. Set to empty to disable.",
+	},
+	cli.StringFlag{
+		Name:  "log.maxsize",
+		Value: "",
+		Usage: "Rotate the access log when it reaches this size. Can be a number or 10KiB/MiB/GiB.",
+	},
+	cli.DurationFlag{
+		Name:  "log.maxage",
+		Value: 0,
+		Usage: "Rotate the access log after this duration.",
+	},
+	cli.BoolFlag{
+		Name:  "log.compress",
+		Usage: "Compress rotated access logs with zstd.",
+	},
+}
+
+// keyFlags select which objects are read.
+var keyFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "key.dist",
+		Value: bench.KeyDistUniform,
+		Usage: "Distribution of objects read. Can be uniform, zipf, hotset or sequential.",
+	},
+	cli.Float64Flag{
+		Name:  "key.skew",
+		Value: 1,
+		Usage: "Exponent of the zipf distribution. Higher values read the most popular objects more often.",
+	},
+	cli.Float64Flag{
+		Name:  "key.hot",
+		Value: 10,
+		Usage: "Percentage of objects in the hot set.",
+	},
+	cli.Float64Flag{
+		Name:  "key.hot.requests",
+		Value: 90,
+		Usage: "Percentage of reads sent to the hot set.",
+	},
 }
@@ -19,72 +19,203 @@ package cli
 
 import (
 	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 
 	humanize "github.com/dustin/go-humanize"
 	"github.com/minio/mc/pkg/probe"
 
 	"github.com/minio/cli"
+	"github.com/minio/warp/pkg/bench"
 	"github.com/minio/warp/pkg/generator"
+	"github.com/minio/warp/pkg/model"
 )
 
 var genFlags = []cli.Flag{
 	cli.StringFlag{
 		Name:  "obj.generator",
 		Value: "random",
-		Usage: "Use specific data generator",
+		Usage: "Use specific data generator. Can be random, csv, seeded or compressible.",
+	},
+	cli.Int64Flag{
+		Name:  "obj.seed",
+		Usage: "Seed of the seeded generator. Object content is a function of the seed and the object name.",
+	},
+	cli.Float64Flag{
+		Name:  "obj.compress.ratio",
+		Value: 2,
+		Usage: "Compression ratio of the compressible generator. 1 is incompressible.",
+	},
+	cli.Float64Flag{
+		Name:  "obj.dedup",
+		Usage: "Percentage of duplicate blocks of the compressible generator.",
+	},
+	cli.StringFlag{
+		Name:  "obj.dedup.block",
+		Value: "4KiB",
+		Usage: "Block size of duplicate blocks of the compressible generator.",
 	},
 	cli.BoolFlag{
 		Name:  "obj.randsize",
 		Usage: "Randomize size of objects so they will be up to the specified size",
 	},
+	cli.StringFlag{
+		Name:  "obj.key",
+		Usage: "Object key template. Can contain {name}, {rand}, {seq}, {time}, {hash}, {dirs}, {utf8} and {special}.",
+	},
+	cli.IntFlag{
+		Name:  "obj.key.depth",
+		Usage: "Number of directory levels of {dirs} in object keys.",
+	},
+	cli.IntFlag{
+		Name:  "obj.key.fanout",
+		Value: 10,
+		Usage: "Number of directories at each level of {dirs} in object keys.",
+	},
+	cli.IntFlag{
+		Name:  "obj.key.length",
+		Usage: "Pad object keys with random characters up to this length.",
+	},
+	cli.StringFlag{
+		Name:  "obj.sizes",
+		Usage: "Object size distribution. Weighted sizes like '4KiB:50,1MiB-4MiB:30', a CSV histogram file or a model file. Overrides obj.size.",
+	},
 }
 
 func newGenSourceCSV(ctx *cli.Context) func() generator.Source {
-	prefixSize := 8
-	if ctx.Bool("noprefix") {
-		prefixSize = 0
-	}
-
 	g := generator.WithCSV().Size(25, 1000)
-
-	size, err := toSize(ctx.String("obj.size"))
-	fatalIf(probe.NewError(err), "Invalid obj.size specified")
-	src, err := generator.NewFn(g.Apply(),
-		generator.WithPrefixSize(prefixSize),
-		generator.WithSize(int64(size)),
-		generator.WithRandomSize(ctx.Bool("obj.randsize")),
-	)
+	opts := append([]generator.Option{g.Apply()}, commonGenOpts(ctx)...)
+	src, err := generator.NewFn(opts...)
 	fatalIf(probe.NewError(err), "Unable to create data generator")
 	return src
 }
 
 // newGenSource returns a new generator
 func newGenSource(ctx *cli.Context) func() generator.Source {
-	prefixSize := 8
-	if ctx.Bool("noprefix") {
-		prefixSize = 0
-	}
-
 	var g generator.OptionApplier
 	switch ctx.String("obj.generator") {
 	case "random":
 		g = generator.WithRandomData()
 	case "csv":
 		g = generator.WithCSV().Size(25, 1000)
+	case "seeded":
+		g = generator.WithSeededData().Seed(ctx.Int64("obj.seed"))
+	case "compressible":
+		bs, err := toSize(ctx.String("obj.dedup.block"))
+		fatalIf(probe.NewError(err), "Invalid obj.dedup.block specified")
+		g = generator.WithCompressible().
+			Ratio(ctx.Float64("obj.compress.ratio")).
+			Dedup(ctx.Float64("obj.dedup")).
+			BlockSize(int(bs))
 	default:
 		err := errors.New("unknown generator type:" + ctx.String("generator"))
 		fatal(probe.NewError(err), "Invalid -generator parameter")
 		return nil
 	}
+	opts := append([]generator.Option{g.Apply()}, commonGenOpts(ctx)...)
+	src, err := generator.NewFn(opts...)
+	fatalIf(probe.NewError(err), "Unable to create data generator")
+	return src
+}
+
+// commonGenOpts returns the generator options shared by all data generators.
+func commonGenOpts(ctx *cli.Context) []generator.Option {
+	prefixSize := 8
+	if ctx.Bool("noprefix") {
+		prefixSize = 0
+	}
 	size, err := toSize(ctx.String("obj.size"))
 	fatalIf(probe.NewError(err), "Invalid obj.size specified")
-	src, err := generator.NewFn(g.Apply(),
+	opts := []generator.Option{
 		generator.WithPrefixSize(prefixSize),
 		generator.WithSize(int64(size)),
 		generator.WithRandomSize(ctx.Bool("obj.randsize")),
-	)
-	fatalIf(probe.NewError(err), "Unable to create data generator")
-	return src
+	}
+	if spec := ctx.String("obj.sizes"); spec != "" {
+		sizes, err := parseSizeDistribution(spec)
+		fatalIf(probe.NewError(err), "Invalid obj.sizes specified")
+		opts = append(opts, generator.WithSizeDistribution(sizes))
+	}
+	if k := newKeyOpts(ctx); k != nil {
+		opts = append(opts, k.Apply())
+	}
+	if n := ctx.Int("prefixes"); n > 0 {
+		if prefixSize == 0 {
+			fatal(probe.NewError(errors.New("cannot be combined with --noprefix")), "Invalid prefixes specified")
+		}
+		opts = append(opts, generator.WithPrefixFanout(n, ctx.Float64("prefix.skew")))
+	}
+	return opts
+}
+
+// newVerifier returns a verifier if verification is enabled.
+// With the seeded generator objects uploaded by other clients or runs are verified as well.
+func newVerifier(ctx *cli.Context) *bench.Verifier {
+	if !ctx.Bool("verify") {
+		return nil
+	}
+	if ctx.String("obj.generator") == "seeded" {
+		return bench.NewSeededVerifier(ctx.Int64("obj.seed"))
+	}
+	return bench.NewVerifier()
+}
+
+// newKeyOpts returns the object key options, or nil if keys are not customized.
+func newKeyOpts(ctx *cli.Context) *generator.KeyOpts {
+	if ctx.String("obj.key") == "" && ctx.Int("obj.key.depth") == 0 && ctx.Int("obj.key.length") == 0 {
+		return nil
+	}
+	k := generator.WithKeys().
+		Depth(ctx.Int("obj.key.depth")).
+		Fanout(ctx.Int("obj.key.fanout")).
+		Length(ctx.Int("obj.key.length"))
+	if t := ctx.String("obj.key"); t != "" {
+		k = k.Template(t)
+	}
+	return &k
+}
+
+// parseSizeDistribution returns the size distribution specified.
+// If spec is an existing file it is read as a model if it has a .json extension
+// and as a CSV histogram otherwise.
+// Otherwise it is parsed as a list of weighted sizes.
+func parseSizeDistribution(spec string) (generator.SizeDistribution, error) {
+	if _, err := os.Stat(spec); err != nil {
+		return generator.ParseSizeDistribution(spec)
+	}
+	if strings.EqualFold(filepath.Ext(spec), ".json") {
+		m, err := model.LoadFile(spec)
+		if err != nil {
+			return nil, err
+		}
+		// Prefer the sizes of uploads, since these are the objects created.
+		for _, method := range []string{http.MethodPut, http.MethodGet} {
+			op := m.Ops[method]
+			if op == nil || len(op.Sizes) == 0 {
+				continue
+			}
+			res := make(generator.SizeDistribution, 0, len(op.Sizes))
+			for _, b := range op.Sizes {
+				if b.Max <= 0 {
+					continue
+				}
+				if b.Min <= 0 {
+					b.Min = 1
+				}
+				res = append(res, generator.SizeBucket{Min: b.Min, Max: b.Max, Weight: b.Weight})
+			}
+			return res, res.Validate()
+		}
+		return nil, errors.New("model has no object sizes")
+	}
+	f, err := os.Open(spec)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return generator.ReadSizeHistogram(f)
 }
 
 // toSize converts a size indication to bytes.
@@ -18,9 +18,14 @@
 package cli
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio/pkg/console"
+	"github.com/minio/warp/pkg/accesslog"
 	"github.com/minio/warp/pkg/bench"
 )
 
@@ -40,20 +45,19 @@ var (
 			Name:  "range",
 			Usage: "Do ranged get operations. Will request with random offset and length.",
 		},
-		cli.StringFlag{
-			Name:  "logpath",
-			Value: "./test.log",
-			Usage: "access log.",
-		},
 		cli.StringFlag{
 			Name:  "putlogpath",
 			Value: "",
-			Usage: "put access log.",
+			Usage: "Download objects uploaded according to this access log instead of uploading objects.",
 		},
 		cli.StringFlag{
 			Name:  "logtype",
 			Value: "normal",
-			Usage: "normal | xstore",
+			Usage: fmt.Sprintf("Format of --putlogpath. Can be %s.", strings.Join(accesslog.Formats(), ", ")),
+		},
+		cli.BoolFlag{
+			Name:  "verify",
+			Usage: "Verify the content of downloaded objects. Corrupt and stale reads are reported as errors.",
 		},
 	}
 )
@@ -63,7 +67,7 @@ var getCmd = cli.Command{
 	Usage:  "benchmark get objects",
 	Action: mainGet,
 	Before: setGlobalsFromContext,
-	Flags:  combineFlags(globalFlags, ioFlags, getFlags, genFlags, benchFlags, analyzeFlags),
+	Flags:  combineFlags(globalFlags, ioFlags, getFlags, keyFlags, genFlags, benchFlags, analyzeFlags),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -93,10 +97,13 @@ func mainGet(ctx *cli.Context) error {
 		RandomRanges:  ctx.Bool("range"),
 		CreateObjects: ctx.Int("objects"),
 		GetOpts:       minio.GetObjectOptions{ServerSideEncryption: sse},
-		LogPath:       ctx.String("logpath"),    // add by guo.hao
-		PutLogPath:    ctx.String("putlogpath"), // add by guo.hao
-		LogType:       ctx.String("logtype"),    // add by guo.hao
 	}
+	if path := ctx.String("putlogpath"); path != "" {
+		entries, err := accesslog.ReadFile(path, ctx.String("logtype"))
+		fatalIf(probe.NewError(err), "Unable to read access log")
+		b.PutLog = entries
+	}
+	b.Verify = newVerifier(ctx)
 	return runBench(ctx, &b)
 }
 
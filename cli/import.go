@@ -0,0 +1,106 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+	"github.com/minio/warp/pkg/accesslog"
+	"github.com/minio/warp/pkg/bench"
+)
+
+var importFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "format",
+		Value: "normal",
+		Usage: fmt.Sprintf("Access log format. Can be %s.", strings.Join(accesslog.Formats(), ", ")),
+	},
+	cli.StringFlag{
+		Name:  "benchdata",
+		Value: "",
+		Usage: "Output benchmark data to this file. By default unique filename is generated.",
+	},
+}
+
+var importCmd = cli.Command{
+	Name:   "import",
+	Usage:  "convert access logs to benchmark data",
+	Action: mainImport,
+	Before: setGlobalsFromContext,
+	Flags:  combineFlags(globalFlags, importFlags),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] access-log1 [access-log2 ...]
+  -> see https://github.com/minio/warp#importing-access-logs
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}`,
+}
+
+// mainImport is the entry point for import command.
+func mainImport(ctx *cli.Context) error {
+	checkImportSyntax(ctx)
+	var entries accesslog.Entries
+	for _, name := range ctx.Args() {
+		e, err := accesslog.ReadFile(name, ctx.String("format"))
+		fatalIf(probe.NewError(err), "Unable to read access log")
+		entries = append(entries, e...)
+	}
+	if len(entries) == 0 {
+		return errors.New("access logs contain no requests")
+	}
+	ops, err := bench.OperationsFromAccessLog(entries)
+	fatalIf(probe.NewError(err), "Unable to convert access log")
+
+	fileName := ctx.String("benchdata")
+	if fileName == "" {
+		fileName = fmt.Sprintf("%s-%s-%s", appName, ctx.Command.Name, time.Now().Format("2006-01-02[150405]"))
+	}
+	f, err := os.Create(fileName + ".csv.zst")
+	fatalIf(probe.NewError(err), "Unable to write benchmark data")
+	defer f.Close()
+	enc, err := zstd.NewWriter(f, zstd.WithEncoderLevel(zstd.SpeedBetterCompression))
+	fatalIf(probe.NewError(err), "Unable to compress benchmark output")
+	err = ops.CSV(enc, commandLine(ctx))
+	fatalIf(probe.NewError(err), "Unable to write benchmark output")
+	err = enc.Close()
+	fatalIf(probe.NewError(err), "Unable to write benchmark output")
+
+	console.Infof("Imported %d requests from %d clients. Benchmark data written to %q\n", len(ops), ops.Clients(), fileName+".csv.zst")
+	return nil
+}
+
+func checkImportSyntax(ctx *cli.Context) {
+	if ctx.NArg() == 0 {
+		console.Fatal("At least one access log must be supplied")
+	}
+	if _, err := accesslog.GetParser(ctx.String("format")); err != nil {
+		console.Fatal(err)
+	}
+}
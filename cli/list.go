@@ -80,6 +80,9 @@ func checkListSyntax(ctx *cli.Context) {
 	if ctx.NArg() > 0 {
 		console.Fatal("Command takes no arguments")
 	}
+	if ctx.Int("prefixes") > 0 {
+		console.Fatal("--prefixes is not supported, since each thread lists its own prefix")
+	}
 
 	checkAnalyze(ctx)
 	checkBenchmark(ctx)
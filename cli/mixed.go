@@ -59,6 +59,22 @@ var (
 			Usage: "The amount of DELETE operations. Must be at least the same as PUT.",
 			Value: 10,
 		},
+		cli.BoolFlag{
+			Name:  "verify",
+			Usage: "Verify the content of downloaded objects. Corrupt and stale reads are reported as errors.",
+		},
+		cli.StringFlag{
+			Name:  "sequence",
+			Usage: "Run object lifecycles instead of random operations, for example 'PUT,GET*3,STAT,DELETE'.",
+		},
+		cli.DurationFlag{
+			Name:  "sequence.think",
+			Usage: "Time to wait between the steps of a sequence.",
+		},
+		cli.DurationFlag{
+			Name:  "sequence.think.max",
+			Usage: "Randomize the time between steps up to this value.",
+		},
 	}
 )
 
@@ -67,7 +83,7 @@ var mixedCmd = cli.Command{
 	Usage:  "benchmark mixed objects",
 	Action: mainMixed,
 	Before: setGlobalsFromContext,
-	Flags:  combineFlags(globalFlags, ioFlags, mixedFlags, genFlags, benchFlags, analyzeFlags),
+	Flags:  combineFlags(globalFlags, ioFlags, mixedFlags, keyFlags, genFlags, benchFlags, analyzeFlags),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -111,6 +127,13 @@ func mainMixed(ctx *cli.Context) error {
 		},
 		Dist: &dist,
 	}
+	b.Verify = newVerifier(ctx)
+	if s := ctx.String("sequence"); s != "" {
+		b.Sequence, err = bench.ParseSequence(s)
+		fatalIf(probe.NewError(err), "Invalid sequence")
+		b.Sequence.Think = ctx.Duration("sequence.think")
+		b.Sequence.ThinkMax = ctx.Duration("sequence.think.max")
+	}
 	return runBench(ctx, &b)
 }
 
@@ -118,6 +141,14 @@ func checkMixedSyntax(ctx *cli.Context) {
 	if ctx.NArg() > 0 {
 		console.Fatal("Command takes no arguments")
 	}
+	if s := ctx.String("sequence"); s != "" {
+		if _, err := bench.ParseSequence(s); err != nil {
+			console.Fatal("Invalid sequence: ", err)
+		}
+		if ctx.Duration("sequence.think") < 0 || ctx.Duration("sequence.think.max") < 0 {
+			console.Fatal("sequence think times cannot be negative")
+		}
+	}
 
 	checkAnalyze(ctx)
 	checkBenchmark(ctx)
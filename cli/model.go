@@ -0,0 +1,126 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+	"github.com/minio/warp/pkg/accesslog"
+	"github.com/minio/warp/pkg/model"
+)
+
+var modelFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "format",
+		Value: "normal",
+		Usage: fmt.Sprintf("Access log format. Can be %s.", strings.Join(accesslog.Formats(), ", ")),
+	},
+	cli.StringFlag{
+		Name:  "output",
+		Value: "warp-model.json",
+		Usage: "Write the model to this file.",
+	},
+}
+
+var modelCmd = cli.Command{
+	Name:   "model",
+	Usage:  "create a workload model from access logs",
+	Action: mainModel,
+	Before: setGlobalsFromContext,
+	Flags:  combineFlags(globalFlags, modelFlags),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+  The model contains the operation mix, object sizes, object popularity,
+  reads of recently written objects and the request rate over time.
+  Run the model with 'warp modeled'.
+
+USAGE:
+  {{.HelpName}} [FLAGS] access-log [access-log...]
+  -> see https://github.com/minio/warp#modeled
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}`,
+}
+
+// mainModel is the entry point for model command.
+func mainModel(ctx *cli.Context) error {
+	checkModelSyntax(ctx)
+	var entries accesslog.Entries
+	for _, name := range ctx.Args() {
+		e, err := accesslog.ReadFile(name, ctx.String("format"))
+		fatalIf(probe.NewError(err), "Unable to read access log")
+		entries = append(entries, e...)
+	}
+	if len(entries) == 0 {
+		return errors.New("access logs contain no requests")
+	}
+	m, err := model.Fit(entries)
+	fatalIf(probe.NewError(err), "Unable to create model")
+
+	f, err := os.Create(ctx.String("output"))
+	fatalIf(probe.NewError(err), "Unable to create model file")
+	defer f.Close()
+	err = m.Save(f)
+	fatalIf(probe.NewError(err), "Unable to write model")
+
+	printModel(m)
+	console.Infof("Model written to %q\n", ctx.String("output"))
+	return nil
+}
+
+// printModel prints a summary of a model.
+func printModel(m *model.Model) {
+	console.Printf("Requests: %d over %v, %.2f req/s.\n", m.Requests, m.Duration.Round(time.Second), m.MeanRate())
+	methods := make([]string, 0, len(m.Ops))
+	for method := range m.Ops {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	for _, method := range methods {
+		op := m.Ops[method]
+		console.Printf(" * %s: %.1f%% of requests, %.2f%% errors", method, op.Share*100, op.ErrRatio*100)
+		if len(op.Sizes) > 0 {
+			console.Printf(", average size %s", humanize.IBytes(uint64(op.Sizes.Mean())))
+		}
+		console.Printf(".\n")
+	}
+	console.Printf("Objects read: %d, popularity skew: %.2f.\n", m.Popularity.Keys, m.Popularity.Skew)
+	if m.ReadAfterWrite.Ratio > 0 {
+		console.Printf("Reads of written objects: %.1f%%, average delay: %v.\n", m.ReadAfterWrite.Ratio*100, time.Duration(m.ReadAfterWrite.Delay.Mean()).Round(time.Millisecond))
+	}
+}
+
+func checkModelSyntax(ctx *cli.Context) {
+	if ctx.NArg() == 0 {
+		console.Fatal("At least one access log must be supplied")
+	}
+	if _, err := accesslog.GetParser(ctx.String("format")); err != nil {
+		console.Fatal(err)
+	}
+}
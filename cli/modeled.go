@@ -0,0 +1,119 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio/pkg/console"
+	"github.com/minio/warp/pkg/bench"
+	"github.com/minio/warp/pkg/generator"
+	"github.com/minio/warp/pkg/model"
+)
+
+var modeledFlags = []cli.Flag{
+	cli.Float64Flag{
+		Name:  "scale",
+		Value: 1,
+		Usage: "Multiply the request rate of the model.",
+	},
+	cli.IntFlag{
+		Name:  "objects",
+		Value: 0,
+		Usage: "Number of objects to upload. By default the number of objects read in the model.",
+	},
+}
+
+var modeledCmd = cli.Command{
+	Name:   "modeled",
+	Usage:  "benchmark a workload model",
+	Action: mainModeled,
+	Before: setGlobalsFromContext,
+	Flags:  combineFlags(globalFlags, ioFlags, modeledFlags, benchFlags, analyzeFlags),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+  Models are created from access logs with 'warp model'.
+  Requests arrive at the rate of the model, which is repeated until -duration has been reached.
+
+USAGE:
+  {{.HelpName}} [FLAGS] model-file
+  -> see https://github.com/minio/warp#modeled
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}`,
+}
+
+// mainModeled is the entry point for modeled command.
+func mainModeled(ctx *cli.Context) error {
+	checkModeledSyntax(ctx)
+	m, err := model.LoadFile(ctx.Args().First())
+	fatalIf(probe.NewError(err), "Unable to load model")
+
+	prefixSize := 8
+	if ctx.Bool("noprefix") {
+		prefixSize = 0
+	}
+	size := m.MaxSize()
+	if size < 1 {
+		size = 1
+	}
+	src, err := generator.NewFn(generator.WithRandomData().Apply(),
+		generator.WithPrefixSize(prefixSize),
+		generator.WithSize(size),
+	)
+	fatalIf(probe.NewError(err), "Unable to create data generator")
+
+	sse := newSSE(ctx)
+	b := bench.Modeled{
+		Common: bench.Common{
+			Client:      newClient(ctx),
+			Concurrency: ctx.Int("concurrent"),
+			Source:      src,
+			Bucket:      ctx.String("bucket"),
+			Location:    "",
+			PutOpts:     putOpts(ctx),
+		},
+		Model:         m,
+		Scale:         ctx.Float64("scale"),
+		CreateObjects: ctx.Int("objects"),
+		GetOpts:       minio.GetObjectOptions{ServerSideEncryption: sse},
+		StatOpts:      minio.StatObjectOptions{ServerSideEncryption: sse},
+	}
+	return runBench(ctx, &b)
+}
+
+func checkModeledSyntax(ctx *cli.Context) {
+	if ctx.NArg() != 1 {
+		console.Fatal("A single model file must be supplied")
+	}
+	if ctx.Float64("scale") <= 0 {
+		console.Fatal("scale must be > 0")
+	}
+	if ctx.Int("objects") < 0 {
+		console.Fatal("objects must be >= 0")
+	}
+	if ctx.Float64("rps") > 0 {
+		console.Fatal("rps cannot be used, requests are scheduled by the benchmark")
+	}
+
+	checkAnalyze(ctx)
+	checkBenchmark(ctx)
+}
@@ -0,0 +1,135 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+	"github.com/minio/warp/pkg/bench"
+)
+
+var (
+	multipartFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "obj.size",
+			Value: "50MiB",
+			Usage: "Size of each generated object. Can be a number or 10KiB/MiB/GiB. All sizes are base 2 binary.",
+		},
+		cli.StringFlag{
+			Name:  "part.size",
+			Value: "5MiB",
+			Usage: "Size of each uploaded part. The last part of an object may be smaller.",
+		},
+		cli.IntFlag{
+			Name:  "parts.concurrent",
+			Value: 4,
+			Usage: "Number of parts of each object uploaded concurrently.",
+		},
+		cli.Float64Flag{
+			Name:  "abort",
+			Value: 0,
+			Usage: "Fraction of uploads aborted instead of completed, between 0 and 1.",
+		},
+	}
+)
+
+// Multipart command.
+var multipartCmd = cli.Command{
+	Name:   "multipart",
+	Usage:  "benchmark multipart uploads",
+	Action: mainMultipart,
+	Before: setGlobalsFromContext,
+	Flags:  combineFlags(globalFlags, ioFlags, multipartFlags, genFlags, benchFlags, analyzeFlags),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS]
+  -> see https://github.com/minio/warp#multipart
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}`,
+}
+
+// mainMultipart is the entry point for multipart command.
+func mainMultipart(ctx *cli.Context) error {
+	checkMultipartSyntax(ctx)
+	src := newGenSource(ctx)
+	partSize, _ := toSize(ctx.String("part.size"))
+	b := bench.Multipart{
+		Common: bench.Common{
+			Client:      newClient(ctx),
+			Concurrency: ctx.Int("concurrent"),
+			Source:      src,
+			Bucket:      ctx.String("bucket"),
+			Location:    "",
+			PutOpts:     putOpts(ctx),
+		},
+		PartSize:      int64(partSize),
+		PartsInFlight: ctx.Int("parts.concurrent"),
+		AbortRatio:    ctx.Float64("abort"),
+	}
+	return runBench(ctx, &b)
+}
+
+// S3 limits of the size of parts.
+const (
+	minPartSize = 5 << 20
+	maxPartSize = 5 << 30
+)
+
+// multipartMaxObjSize returns the biggest object size that will be uploaded.
+func multipartMaxObjSize(ctx *cli.Context) int64 {
+	if spec := ctx.String("obj.sizes"); spec != "" {
+		sizes, err := parseSizeDistribution(spec)
+		fatalIf(probe.NewError(err), "Invalid obj.sizes specified")
+		return sizes.MaxSize()
+	}
+	size, err := toSize(ctx.String("obj.size"))
+	fatalIf(probe.NewError(err), "Invalid obj.size specified")
+	return int64(size)
+}
+
+func checkMultipartSyntax(ctx *cli.Context) {
+	if ctx.NArg() > 0 {
+		console.Fatal("Command takes no arguments")
+	}
+	sz, err := toSize(ctx.String("part.size"))
+	if err != nil || sz == 0 {
+		console.Fatal("Invalid --part.size: ", ctx.String("part.size"))
+	}
+	if sz > maxPartSize {
+		console.Fatal("--part.size must be at most ", humanize.IBytes(maxPartSize))
+	}
+	// Only the last part of an object may be smaller than the minimum part size.
+	if sz < minPartSize && multipartMaxObjSize(ctx) > int64(sz) {
+		console.Fatal("--part.size must be at least ", humanize.IBytes(minPartSize), " when objects have more than one part")
+	}
+	if ctx.Int("parts.concurrent") <= 0 {
+		console.Fatal("--parts.concurrent must be > 0")
+	}
+	if r := ctx.Float64("abort"); r < 0 || r > 1 {
+		console.Fatal("--abort must be between 0 and 1")
+	}
+
+	checkAnalyze(ctx)
+	checkBenchmark(ctx)
+}
@@ -18,6 +18,9 @@
 package cli
 
 import (
+	"math/rand"
+	"time"
+
 	"github.com/minio/cli"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio/pkg/console"
@@ -31,11 +34,6 @@ var (
 			Value: "10MiB",
 			Usage: "Size of each generated object. Can be a number or 10KiB/MiB/GiB. All sizes are base 2 binary.",
 		},
-		cli.StringFlag{
-			Name:  "logpath",
-			Value: "./test.log",
-			Usage: "access log.",
-		},
 	}
 )
 
@@ -71,19 +69,26 @@ func mainPut(ctx *cli.Context) error {
 			Location:    "",
 			PutOpts:     putOpts(ctx),
 		},
-		LogPath: ctx.String("logpath"), // add by guo.hao
 	}
 	return runBench(ctx, &b)
 }
 
 // putOpts retrieves put options from the context.
 func putOpts(ctx *cli.Context) minio.PutObjectOptions {
-	return minio.PutObjectOptions{
+	opts := minio.PutObjectOptions{
 		ServerSideEncryption: newSSE(ctx),
 		DisableMultipart:     ctx.Bool("disable-multipart"),
 		SendContentMd5:       ctx.Bool("md5"),
 		StorageClass:         ctx.String("storage-class"),
 	}
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	if n := ctx.Int("tags"); n > 0 {
+		opts.UserTags = bench.RandomTags(rng, "warp", n, ctx.Int("tag.size"))
+	}
+	if n := ctx.Int("metadata"); n > 0 {
+		opts.UserMetadata = bench.RandomTags(rng, "warp", n, ctx.Int("metadata.size"))
+	}
+	return opts
 }
 
 func checkPutSyntax(ctx *cli.Context) {
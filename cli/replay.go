@@ -0,0 +1,123 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio/pkg/console"
+	"github.com/minio/warp/pkg/accesslog"
+	"github.com/minio/warp/pkg/bench"
+	"github.com/minio/warp/pkg/generator"
+)
+
+var (
+	replayFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "format",
+			Value: "normal",
+			Usage: fmt.Sprintf("Access log format. Can be %s.", strings.Join(accesslog.Formats(), ", ")),
+		},
+		cli.Float64Flag{
+			Name:  "speed",
+			Value: 1,
+			Usage: "Replay speed. 2 will replay requests twice as fast as they were recorded.",
+		},
+	}
+)
+
+var replayCmd = cli.Command{
+	Name:   "replay",
+	Usage:  "replay requests from access logs",
+	Action: mainReplay,
+	Before: setGlobalsFromContext,
+	Flags:  combineFlags(globalFlags, ioFlags, replayFlags, benchFlags, analyzeFlags),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+  PUT, GET, HEAD and DELETE requests are sent to the bucket with the recorded timing.
+  Objects that are read before they are written in the log are uploaded before the replay starts.
+  If the logs contain requests to several buckets, object names are prefixed by the bucket in the log.
+  The benchmark will end when all requests have been sent or the duration specified with -duration has been reached.
+
+USAGE:
+  {{.HelpName}} [FLAGS] access-log [access-log...]
+  -> see https://github.com/minio/warp#replay
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}`,
+}
+
+// mainReplay is the entry point for replay command.
+func mainReplay(ctx *cli.Context) error {
+	checkReplaySyntax(ctx)
+	var reqs accesslog.Entries
+	for _, name := range ctx.Args() {
+		entries, err := accesslog.ReadFile(name, ctx.String("format"))
+		fatalIf(probe.NewError(err), "Unable to read access log")
+		reqs = append(reqs, entries...)
+	}
+	reqs.SortByTime()
+	console.Infoln("Loaded", len(reqs), "requests")
+
+	var maxSize int64 = 1
+	for _, e := range reqs {
+		if e.Size > maxSize {
+			maxSize = e.Size
+		}
+	}
+	src, err := generator.NewFn(generator.WithRandomData().Apply(), generator.WithSize(maxSize))
+	fatalIf(probe.NewError(err), "Unable to create data generator")
+
+	sse := newSSE(ctx)
+	b := bench.Replay{
+		Common: bench.Common{
+			Client:      newClient(ctx),
+			Concurrency: ctx.Int("concurrent"),
+			Source:      src,
+			Bucket:      ctx.String("bucket"),
+			Location:    "",
+			PutOpts:     putOpts(ctx),
+		},
+		Requests: reqs,
+		Speed:    ctx.Float64("speed"),
+		GetOpts:  minio.GetObjectOptions{ServerSideEncryption: sse},
+		StatOpts: minio.StatObjectOptions{ServerSideEncryption: sse},
+	}
+	return runBench(ctx, &b)
+}
+
+func checkReplaySyntax(ctx *cli.Context) {
+	if ctx.NArg() == 0 {
+		console.Fatal("No access log supplied")
+	}
+	if ctx.Float64("speed") <= 0 {
+		console.Fatal("speed must be > 0")
+	}
+	if ctx.Float64("rps") > 0 {
+		console.Fatal("rps cannot be used, requests are scheduled by the benchmark")
+	}
+
+	checkAnalyze(ctx)
+	checkBenchmark(ctx)
+}
@@ -0,0 +1,149 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio/pkg/console"
+	"github.com/minio/warp/pkg/bench"
+)
+
+var (
+	replicateFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "obj.size",
+			Value: "10KiB",
+			Usage: "Size of each generated object. Can be a number or 10KiB/MiB/GiB. All sizes are base 2 binary.",
+		},
+		cli.StringFlag{
+			Name:   "remote.host",
+			Usage:  "Replication target host. Multiple hosts can be specified as a comma separated list.",
+			EnvVar: appNameUC + "_REMOTE_HOST",
+		},
+		cli.StringFlag{
+			Name:   "remote.access-key",
+			Usage:  "Replication target access key. Uses --access-key if not set.",
+			EnvVar: appNameUC + "_REMOTE_ACCESS_KEY",
+		},
+		cli.StringFlag{
+			Name:   "remote.secret-key",
+			Usage:  "Replication target secret key. Uses --secret-key if not set.",
+			EnvVar: appNameUC + "_REMOTE_SECRET_KEY",
+		},
+		cli.BoolFlag{
+			Name:   "remote.tls",
+			Usage:  "Use TLS (HTTPS) for the replication target",
+			EnvVar: appNameUC + "_REMOTE_TLS",
+		},
+		cli.StringFlag{
+			Name:   "remote.region",
+			Usage:  "Specify a custom region for the replication target",
+			EnvVar: appNameUC + "_REMOTE_REGION",
+		},
+		cli.StringFlag{
+			Name:  "remote.bucket",
+			Usage: "Bucket on the replication target. Uses the same bucket name if not set.",
+		},
+		cli.Float64Flag{
+			Name:  "delete-distrib",
+			Usage: "Percentage of writes that delete a replicated object.",
+			Value: 0,
+		},
+		cli.DurationFlag{
+			Name:  "lag.poll",
+			Value: 100 * time.Millisecond,
+			Usage: "Time between polls of the replication target.",
+		},
+		cli.DurationFlag{
+			Name:  "lag.timeout",
+			Value: time.Minute,
+			Usage: "Report objects that are not replicated within this time.",
+		},
+	}
+)
+
+var replicateCmd = cli.Command{
+	Name:   "replicate",
+	Usage:  "measure replication lag between two sets of endpoints",
+	Action: mainReplicate,
+	Before: setGlobalsFromContext,
+	Flags:  combineFlags(globalFlags, ioFlags, replicateFlags, genFlags, benchFlags, analyzeFlags),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+  Objects are uploaded to --host and polled on --remote.host until they have been replicated.
+  Replication must be configured between the buckets before running the benchmark.
+
+USAGE:
+  {{.HelpName}} [FLAGS]
+  -> see https://github.com/minio/warp#replicate
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}`,
+}
+
+// mainReplicate is the entry point for replicate command.
+func mainReplicate(ctx *cli.Context) error {
+	checkReplicateSyntax(ctx)
+	src := newGenSource(ctx)
+	sse := newSSE(ctx)
+	for _, key := range []string{"access-key", "secret-key"} {
+		if !ctx.IsSet("remote." + key) {
+			ctx.Set("remote."+key, ctx.String(key))
+		}
+	}
+	b := bench.Replicate{
+		Common: bench.Common{
+			Client:      newClient(ctx),
+			Concurrency: ctx.Int("concurrent"),
+			Source:      src,
+			Bucket:      ctx.String("bucket"),
+			Location:    "",
+			PutOpts:     putOpts(ctx),
+		},
+		RemoteClient: newHostsClient(ctx, "remote.", parseHosts(ctx.String("remote.host"))),
+		RemoteBucket: ctx.String("remote.bucket"),
+		DeleteRatio:  ctx.Float64("delete-distrib") / 100,
+		Poll:         ctx.Duration("lag.poll"),
+		Timeout:      ctx.Duration("lag.timeout"),
+		StatOpts:     minio.StatObjectOptions{ServerSideEncryption: sse},
+	}
+	return runBench(ctx, &b)
+}
+
+func checkReplicateSyntax(ctx *cli.Context) {
+	if ctx.NArg() > 0 {
+		console.Fatal("Command takes no arguments")
+	}
+	if ctx.String("remote.host") == "" {
+		console.Fatal("remote.host must be set")
+	}
+	if d := ctx.Float64("delete-distrib"); d < 0 || d > 100 {
+		console.Fatal("delete-distrib must be between 0 and 100")
+	}
+	if ctx.Duration("lag.poll") <= 0 || ctx.Duration("lag.timeout") <= 0 {
+		console.Fatal("lag.poll and lag.timeout must be > 0")
+	}
+
+	checkAnalyze(ctx)
+	checkBenchmark(ctx)
+}
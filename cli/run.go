@@ -0,0 +1,295 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+	"github.com/minio/warp/api"
+	"github.com/minio/warp/pkg/accesslog"
+	"github.com/minio/warp/pkg/bench"
+	"gopkg.in/yaml.v2"
+)
+
+var runFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "benchdata",
+		Value: "",
+		Usage: "Output benchmark data to this file. By default unique filename is generated.",
+	},
+}
+
+var runCmd = cli.Command{
+	Name:   "run",
+	Usage:  "run the phases of a scenario file",
+	Action: mainRun,
+	Before: setGlobalsFromContext,
+	Flags:  combineFlags(globalFlags, runFlags, analyzeFlags),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+  Each phase runs a benchmark with the flags specified in the scenario.
+  Phases with "noclear: true" don't clear the bucket, but only use objects they upload themselves.
+  The operations of all phases are saved to a single benchmark data file.
+
+USAGE:
+  {{.HelpName}} [FLAGS] scenario.yaml
+  -> see https://github.com/minio/warp#run
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}`,
+}
+
+// scenario is a series of benchmark phases.
+type scenario struct {
+	// Flags are applied to all phases.
+	Flags  map[string]interface{} `yaml:"flags"`
+	Phases []scenarioPhase        `yaml:"phases"`
+}
+
+// scenarioPhase is a single benchmark run of a scenario.
+type scenarioPhase struct {
+	Name      string                 `yaml:"name"`
+	Benchmark string                 `yaml:"benchmark"`
+	Args      []string               `yaml:"args"`
+	Flags     map[string]interface{} `yaml:"flags"`
+	Duration  time.Duration          `yaml:"duration"`
+	Ramp      time.Duration          `yaml:"ramp"`
+	// NoClear leaves the data of the previous phase in the bucket.
+	// The phase still uploads and uses its own objects.
+	NoClear bool `yaml:"noclear"`
+}
+
+// loadScenario reads and validates a scenario file.
+func loadScenario(fileName string) (*scenario, error) {
+	b, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	var sc scenario
+	if err := yaml.UnmarshalStrict(b, &sc); err != nil {
+		return nil, err
+	}
+	if len(sc.Phases) == 0 {
+		return nil, errors.New("scenario has no phases")
+	}
+	names := make(map[string]struct{}, len(sc.Phases))
+	for i := range sc.Phases {
+		p := &sc.Phases[i]
+		if p.Benchmark == "" {
+			return nil, fmt.Errorf("phase %d: no benchmark specified", i+1)
+		}
+		if p.Name == "" {
+			p.Name = fmt.Sprintf("%d-%s", i+1, p.Benchmark)
+		}
+		if _, ok := names[p.Name]; ok {
+			return nil, fmt.Errorf("phase %d: duplicate name %q", i+1, p.Name)
+		}
+		names[p.Name] = struct{}{}
+		if p.Duration < 0 || p.Ramp < 0 {
+			return nil, fmt.Errorf("phase %q: duration and ramp cannot be negative", p.Name)
+		}
+	}
+	return &sc, nil
+}
+
+// scenarioRun contains the state of the currently running phase.
+type scenarioRun struct {
+	phase scenarioPhase
+	// Leave the data in the bucket for the next phase.
+	keepData bool
+	clientID string
+	ops      bench.Operations
+	// kept is the benchmark with data kept for the next phase.
+	kept bench.Benchmark
+	// accessLogs contains the access logs shared by all phases by path.
+	accessLogs map[string]*accesslog.Writer
+	// interrupted is set if the phase was stopped by a signal.
+	interrupted bool
+}
+
+// activeScenario is set while running scenario phases.
+var activeScenario *scenarioRun
+
+// mainRun is the entry point for run command.
+func mainRun(ctx *cli.Context) error {
+	checkRunSyntax(ctx)
+	sc, err := loadScenario(ctx.Args().First())
+	fatalIf(probe.NewError(err), "Unable to load scenario")
+
+	cID := pRandASCII(4)
+	app := registerApp("warp", benchCmds)
+	var ops bench.Operations
+	var kept []bench.Benchmark
+	var interrupted bool
+	accessLogs := make(map[string]*accesslog.Writer)
+	for i, phase := range sc.Phases {
+		cmd := app.Command(phase.Benchmark)
+		if cmd == nil {
+			fatalIf(errDummy(), "Phase %q: unknown benchmark %q", phase.Name, phase.Benchmark)
+		}
+		fs, err := flagSet(cmd.Name, cmd.Flags, phase.Args)
+		fatalIf(probe.NewError(err), "Phase "+phase.Name)
+		ctx2 := cli.NewContext(app, fs, nil)
+		ctx2.Command = *cmd
+		for _, f := range globalFlags {
+			name := strings.Split(f.GetName(), ",")[0]
+			if ctx.IsSet(name) {
+				fatalIf(probe.NewError(ctx2.Set(name, ctx.String(name))), "Phase "+phase.Name)
+			}
+		}
+		for _, flags := range []map[string]interface{}{sc.Flags, phase.Flags} {
+			for k, v := range flags {
+				err := ctx2.Set(k, fmt.Sprint(v))
+				if err != nil {
+					fatalIf(probe.NewError(fmt.Errorf("parsing parameters (%v:%v): %w", k, v, err)), "Phase "+phase.Name)
+				}
+			}
+		}
+		if phase.Duration > 0 {
+			fatalIf(probe.NewError(ctx2.Set("duration", phase.Duration.String())), "Phase "+phase.Name)
+		}
+
+		activeScenario = &scenarioRun{
+			phase:      phase,
+			keepData:   i+1 < len(sc.Phases) && sc.Phases[i+1].NoClear,
+			clientID:   cID,
+			accessLogs: accessLogs,
+		}
+		console.Infof("Phase %d/%d: %s, running %s benchmark.\n", i+1, len(sc.Phases), phase.Name, cmd.Name)
+		err = runCommand(ctx2, cmd)
+		fatalIf(probe.NewError(err), "Phase "+phase.Name)
+		ops = append(ops, activeScenario.ops...)
+		if activeScenario.kept != nil {
+			kept = append(kept, activeScenario.kept)
+		}
+		interrupted = activeScenario.interrupted
+		activeScenario = nil
+		if interrupted {
+			console.Infoln("Interrupted, skipping remaining phases.")
+			break
+		}
+	}
+	for _, w := range accessLogs {
+		if err := w.Close(); err != nil {
+			console.Errorln("Error writing access log:", err)
+		}
+	}
+
+	fileName := ctx.String("benchdata")
+	if fileName == "" {
+		fileName = fmt.Sprintf("%s-%s-%s-%s", appName, ctx.Command.Name, time.Now().Format("2006-01-02[150405]"), cID)
+	}
+	ops.SortByStartTime()
+	f, err := os.Create(fileName + ".csv.zst")
+	fatalIf(probe.NewError(err), "Unable to write benchmark data")
+	defer f.Close()
+	enc, err := zstd.NewWriter(f, zstd.WithEncoderLevel(zstd.SpeedBetterCompression))
+	fatalIf(probe.NewError(err), "Unable to compress benchmark output")
+	err = ops.CSV(enc, commandLine(ctx))
+	fatalIf(probe.NewError(err), "Unable to write benchmark output")
+	err = enc.Close()
+	fatalIf(probe.NewError(err), "Unable to write benchmark output")
+	console.Infof("Benchmark data written to %q\n", fileName+".csv.zst")
+
+	printAnalysis(ctx, ops)
+	if len(kept) > 0 {
+		console.Infoln("Cleaning up data kept between phases...")
+		for _, b := range kept {
+			b.Cleanup(context.Background())
+		}
+	}
+	if interrupted {
+		return errInterrupted
+	}
+	return nil
+}
+
+// runPhase runs the benchmark of the current scenario phase.
+// The operations are stored in the scenario run.
+func (r *scenarioRun) runPhase(ctx *cli.Context, b bench.Benchmark) error {
+	c := b.GetCommon()
+	setupCommon(ctx, c, r.accessLog(ctx))
+	if r.phase.Ramp > 0 {
+		c.Ramp = bench.NewRamp(r.phase.Ramp)
+	}
+	c.Clear = !r.phase.NoClear && !ctx.Bool("noclear")
+	bctx, stopSignal := cancelOnSignal(context.Background(), c)
+	defer stopSignal()
+
+	console.Infoln("Preparing server.")
+	if err := b.Prepare(bctx); err != nil {
+		if bctx.Err() == nil {
+			return err
+		}
+		r.interrupted = true
+	} else {
+		monitor := api.NewBenchmarkMonitor("")
+		monitor.SetLnLoggers(printInfo, printError)
+		ops := runStep(bctx, monitor, b, time.Now(), ctx.Duration("duration"), r.phase.Name+":")
+		finishOps(ops, c, r.clientID)
+		ops.SetPhase(r.phase.Name)
+		r.ops = ops
+		r.interrupted = bctx.Err() != nil
+	}
+	if ctx.Bool("keep-data") || ctx.Bool("noclear") {
+		return nil
+	}
+	if r.keepData && !r.interrupted {
+		// The next phase uses the data, so it is deleted when the scenario ends.
+		r.kept = b
+		return nil
+	}
+	console.Infoln("Starting cleanup...")
+	b.Cleanup(context.Background())
+	return nil
+}
+
+// accessLog returns the access log of the phase.
+// Phases logging to the same path share the log, so earlier phases are not overwritten.
+// The logs are closed when all phases have run.
+func (r *scenarioRun) accessLog(ctx *cli.Context) *accesslog.Writer {
+	path := ctx.String("logpath")
+	if path == "" {
+		return nil
+	}
+	w, ok := r.accessLogs[path]
+	if !ok {
+		w = newAccessLog(ctx)
+		r.accessLogs[path] = w
+	}
+	return w
+}
+
+func checkRunSyntax(ctx *cli.Context) {
+	if ctx.NArg() != 1 {
+		console.Fatal("A single scenario file must be supplied")
+	}
+	checkAnalyze(ctx)
+}
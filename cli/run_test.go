@@ -0,0 +1,93 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadScenario(t *testing.T) {
+	dir, err := ioutil.TempDir("", "warp-scenario")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			yaml: `
+flags:
+  host: localhost:9000
+phases:
+  - benchmark: put
+    duration: 1m
+    flags:
+      obj.size: 1MiB
+  - name: mixed
+    benchmark: mixed
+    ramp: 10s
+    noclear: true
+`,
+		},
+		{name: "no-phases", yaml: "flags:\n  host: localhost:9000\n", wantErr: true},
+		{name: "no-benchmark", yaml: "phases:\n  - name: a\n", wantErr: true},
+		{name: "duplicate", yaml: "phases:\n  - name: a\n    benchmark: get\n  - name: a\n    benchmark: put\n", wantErr: true},
+		{name: "negative", yaml: "phases:\n  - benchmark: get\n    ramp: -1s\n", wantErr: true},
+		{name: "unknown-field", yaml: "phases:\n  - benchmark: get\n    keepdata: true\n", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fileName := filepath.Join(dir, test.name+".yaml")
+			if err := ioutil.WriteFile(fileName, []byte(test.yaml), 0644); err != nil {
+				t.Fatal(err)
+			}
+			sc, err := loadScenario(fileName)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error: %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(sc.Phases) != 2 {
+				t.Fatalf("got %d phases, want 2", len(sc.Phases))
+			}
+			first, second := sc.Phases[0], sc.Phases[1]
+			if first.Name != "1-put" || first.Duration != time.Minute || first.NoClear {
+				t.Errorf("unexpected first phase: %+v", first)
+			}
+			if second.Name != "mixed" || second.Ramp != 10*time.Second || !second.NoClear {
+				t.Errorf("unexpected second phase: %+v", second)
+			}
+			if sc.Flags["host"] != "localhost:9000" || first.Flags["obj.size"] != "1MiB" {
+				t.Errorf("unexpected flags: %v, %v", sc.Flags, first.Flags)
+			}
+		})
+	}
+	if _, err := loadScenario(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("expected error loading missing file")
+	}
+}
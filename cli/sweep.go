@@ -0,0 +1,154 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+	"github.com/minio/warp/pkg/aggregate"
+)
+
+var sweepFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "steps.concurrent",
+		Usage: "Comma separated concurrency of each step, for instance 8,16,32,64.",
+	},
+	cli.StringFlag{
+		Name:  "steps.rps",
+		Usage: "Comma separated operations per second of each step, for instance 100,200,400. --concurrent must be high enough for the highest rate.",
+	},
+}
+
+var sweepCmd = cli.Command{
+	Name:   "sweep",
+	Usage:  "run a benchmark through a series of concurrency or rate steps",
+	Before: setGlobalsFromContext,
+	Flags:  globalFlags,
+}
+
+// sweepCommands returns the benchmarks that can be swept.
+// Each benchmark gets the sweep flags added.
+func sweepCommands(cmds []cli.Command) []cli.Command {
+	var res []cli.Command
+	for _, cmd := range cmds {
+		switch cmd.Name {
+		case replayCmd.Name, modeledCmd.Name:
+			// Requests are scheduled by the benchmark.
+			continue
+		}
+		cmd.Flags = combineFlags(cmd.Flags, sweepFlags)
+		res = append(res, cmd)
+	}
+	return res
+}
+
+// sweepStep is a single step of a sweep.
+type sweepStep struct {
+	name        string
+	concurrency int
+	rps         float64
+}
+
+// sweepSteps returns the steps specified by the context, or nil if not sweeping.
+func sweepSteps(ctx *cli.Context) []sweepStep {
+	conc, rps := ctx.String("steps.concurrent"), ctx.String("steps.rps")
+	if conc == "" && rps == "" {
+		return nil
+	}
+	if ctx.String("warp-client") != "" {
+		fatalIf(errDummy(), "sweep cannot be used with remote clients")
+	}
+	if ctx.Float64("rps") > 0 {
+		fatalIf(errDummy(), "rps cannot be used when sweeping")
+	}
+	steps, err := parseSweepSteps(conc, rps)
+	fatalIf(probe.NewError(err), "Invalid sweep steps")
+	return steps
+}
+
+// parseSweepSteps parses comma separated concurrency or rate steps.
+// Only one of conc and rps may be set.
+func parseSweepSteps(conc, rps string) ([]sweepStep, error) {
+	if conc != "" && rps != "" {
+		return nil, errors.New("steps.concurrent and steps.rps cannot be combined")
+	}
+	var steps []sweepStep
+	if conc != "" {
+		for _, s := range strings.Split(conc, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid concurrency %q in steps.concurrent", s)
+			}
+			steps = append(steps, sweepStep{name: fmt.Sprintf("concurrent=%d", n), concurrency: n})
+		}
+		return steps, nil
+	}
+	for _, s := range strings.Split(rps, ",") {
+		n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid rate %q in steps.rps", s)
+		}
+		steps = append(steps, sweepStep{name: fmt.Sprintf("rps=%g", n), rps: n})
+	}
+	return steps, nil
+}
+
+// printSweep prints statistics for each sweep step.
+func printSweep(sweep aggregate.Sweep) {
+	var types []string
+	byType := make(map[string][]aggregate.SweepStep)
+	for _, s := range sweep.Steps {
+		if _, ok := byType[s.Type]; !ok {
+			types = append(types, s.Type)
+		}
+		byType[s.Type] = append(byType[s.Type], s)
+	}
+	for _, typ := range types {
+		console.SetColor("Print", color.New(color.FgHiWhite))
+		console.Println("\n----------------------------------------")
+		console.Println("Operation:", typ, "sweep")
+		for _, s := range byType[typ] {
+			console.SetColor("Print", color.New(color.FgWhite))
+			if s.Requests == 0 {
+				console.Printf(" * %s: No successful requests.", s.Step)
+			} else {
+				console.Printf(" * %s: %s. Latency avg: %dms, 50%%: %dms, 90%%: %dms, 99%%: %dms.",
+					s.Step, s.Throughput.StringDetails(false), s.DurAvgMillis, s.DurMedianMillis, s.Dur90Millis, s.Dur99Millis)
+			}
+			if s.Throughput.Errors > 0 {
+				console.SetColor("Print", color.New(color.FgHiRed))
+				console.Print(" Errors: ", s.Throughput.Errors)
+			}
+			console.Println("")
+		}
+		console.SetColor("Print", color.New(color.FgHiWhite))
+		if knee, ok := sweep.Knee[typ]; ok {
+			console.Println("\nKnee:", knee+". Latency grows faster than throughput after this step.")
+		} else {
+			console.Println("\nKnee: Not reached. Throughput grew at least as fast as latency.")
+		}
+	}
+	console.SetColor("Print", color.New(color.FgWhite))
+}
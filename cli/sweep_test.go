@@ -0,0 +1,63 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSweepSteps(t *testing.T) {
+	tests := []struct {
+		conc, rps string
+		want      []sweepStep
+		wantErr   bool
+	}{
+		{
+			conc: "8, 16,32",
+			want: []sweepStep{
+				{name: "concurrent=8", concurrency: 8},
+				{name: "concurrent=16", concurrency: 16},
+				{name: "concurrent=32", concurrency: 32},
+			},
+		},
+		{
+			rps: "0.5,100",
+			want: []sweepStep{
+				{name: "rps=0.5", rps: 0.5},
+				{name: "rps=100", rps: 100},
+			},
+		},
+		{conc: "8,0", wantErr: true},
+		{conc: "8,x", wantErr: true},
+		{conc: "8,", wantErr: true},
+		{rps: "-1", wantErr: true},
+		{conc: "8", rps: "10", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.conc+"/"+test.rps, func(t *testing.T) {
+			got, err := parseSweepSteps(test.conc, test.rps)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error: %v", err, test.wantErr)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
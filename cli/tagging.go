@@ -0,0 +1,102 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"github.com/minio/cli"
+	"github.com/minio/minio/pkg/console"
+	"github.com/minio/warp/pkg/bench"
+)
+
+var (
+	taggingFlags = []cli.Flag{
+		cli.IntFlag{
+			Name:  "objects",
+			Value: 2500,
+			Usage: "Number of objects to upload.",
+		},
+		cli.StringFlag{
+			Name:  "obj.size",
+			Value: "1KiB",
+			Usage: "Size of each generated object. Can be a number or 10KiB/MiB/GiB. All sizes are base 2 binary.",
+		},
+		cli.IntFlag{
+			Name:  "set.tags",
+			Value: 10,
+			Usage: "Number of tags set on each object during the benchmark.",
+		},
+		cli.IntFlag{
+			Name:  "set.tag.size",
+			Value: 16,
+			Usage: "Size of each tag value set during the benchmark.",
+		},
+	}
+)
+
+var taggingCmd = cli.Command{
+	Name:   "tagging",
+	Usage:  "benchmark object tagging",
+	Action: mainTagging,
+	Before: setGlobalsFromContext,
+	Flags:  combineFlags(globalFlags, ioFlags, taggingFlags, keyFlags, genFlags, benchFlags, analyzeFlags),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS]
+  -> see https://github.com/minio/warp#tagging
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}`,
+}
+
+// mainTagging is the entry point for tagging command.
+func mainTagging(ctx *cli.Context) error {
+	checkTaggingSyntax(ctx)
+	src := newGenSource(ctx)
+	b := bench.Tagging{
+		Common: bench.Common{
+			Client:      newClient(ctx),
+			Concurrency: ctx.Int("concurrent"),
+			Source:      src,
+			Bucket:      ctx.String("bucket"),
+			Location:    "",
+			PutOpts:     putOpts(ctx),
+		},
+		CreateObjects: ctx.Int("objects"),
+		Tags:          ctx.Int("set.tags"),
+		TagSize:       ctx.Int("set.tag.size"),
+	}
+	return runBench(ctx, &b)
+}
+
+func checkTaggingSyntax(ctx *cli.Context) {
+	if ctx.NArg() > 0 {
+		console.Fatal("Command takes no arguments")
+	}
+	if ctx.Int("objects") <= 0 {
+		console.Fatal("There must be more than 0 objects.")
+	}
+	if ctx.Int("set.tags") <= 0 || ctx.Int("set.tag.size") < 0 {
+		console.Fatal("--set.tags must be > 0 and --set.tag.size cannot be negative")
+	}
+
+	checkAnalyze(ctx)
+	checkBenchmark(ctx)
+}
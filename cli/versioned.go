@@ -59,6 +59,10 @@ var (
 			Usage: "The amount of DELETE operations. Must be at least the same as PUT.",
 			Value: 10,
 		},
+		cli.BoolFlag{
+			Name:  "verify",
+			Usage: "Verify the content of downloaded objects. Corrupt and stale reads are reported as errors.",
+		},
 	}
 )
 
@@ -111,6 +115,7 @@ func mainVersioned(ctx *cli.Context) error {
 		},
 		Dist: &dist,
 	}
+	b.Verify = newVerifier(ctx)
 	return runBench(ctx, &b)
 }
 
@@ -18,4 +18,5 @@ require (
 	github.com/posener/complete v1.2.3
 	github.com/secure-io/sio-go v0.3.0
 	golang.org/x/net v0.0.0-20201010224723-4f7140c49acb
+	gopkg.in/yaml.v2 v2.2.8
 )
@@ -0,0 +1,77 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package accesslog
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// minioAPIMethods maps MinIO API names to the HTTP method
+// of the corresponding object request.
+var minioAPIMethods = map[string]string{
+	"PutObject":    http.MethodPut,
+	"GetObject":    http.MethodGet,
+	"HeadObject":   http.MethodHead,
+	"DeleteObject": http.MethodDelete,
+}
+
+// parseMinioAudit parses a MinIO audit log entry.
+// Only object uploads, downloads, stats and deletes are returned.
+func parseMinioAudit(line []byte) (Entry, bool) {
+	var rec struct {
+		Time time.Time `json:"time"`
+		API  struct {
+			Name           string `json:"name"`
+			Bucket         string `json:"bucket"`
+			Object         string `json:"object"`
+			StatusCode     int    `json:"statusCode"`
+			TimeToResponse string `json:"timeToResponse"`
+			RX             int64  `json:"rx"`
+			TX             int64  `json:"tx"`
+		} `json:"api"`
+		RemoteHost    string            `json:"remotehost"`
+		RequestHeader map[string]string `json:"requestHeader"`
+	}
+	if err := json.Unmarshal(line, &rec); err != nil || rec.API.Object == "" {
+		return Entry{}, false
+	}
+	method, ok := minioAPIMethods[rec.API.Name]
+	if !ok {
+		return Entry{}, false
+	}
+	// Audit entries are logged when the response has been sent.
+	latency, _ := time.ParseDuration(rec.API.TimeToResponse)
+	e := Entry{
+		Time:     rec.Time.Add(-latency),
+		Method:   method,
+		Bucket:   rec.API.Bucket,
+		Key:      rec.API.Object,
+		Size:     rec.API.RX,
+		Latency:  latency,
+		Status:   rec.API.StatusCode,
+		Err:      statusErr(rec.API.StatusCode),
+		ClientIP: rec.RemoteHost,
+		Endpoint: rec.RequestHeader["Host"],
+	}
+	if method == http.MethodGet {
+		e.Size = rec.API.TX
+	}
+	return e, true
+}
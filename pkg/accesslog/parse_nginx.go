@@ -0,0 +1,92 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package accesslog
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// nginxTimeLayout is the format of $time_local.
+const nginxTimeLayout = "02/Jan/2006:15:04:05 -0700"
+
+// parseNginx parses a line of the nginx "combined" log format,
+// optionally followed by $request_time.
+// Requests are expected to use path style addressing.
+//
+// The combined format does not contain the request body size,
+// so the size of uploads is unknown.
+func parseNginx(line []byte) (Entry, bool) {
+	// $remote_addr - $remote_user [$time_local] "$request" $status $body_bytes_sent "$http_referer" "$http_user_agent" [$request_time]
+	f := splitFields(string(line))
+	if len(f) < 7 {
+		return Entry{}, false
+	}
+	req := strings.Fields(f[4])
+	if len(req) < 2 {
+		return Entry{}, false
+	}
+	u, err := url.ParseRequestURI(req[1])
+	if err != nil {
+		return Entry{}, false
+	}
+	bucket, key := splitPath(u.Path)
+	if key == "" {
+		return Entry{}, false
+	}
+	switch req[0] {
+	case http.MethodPut, http.MethodGet, http.MethodHead, http.MethodDelete:
+	default:
+		return Entry{}, false
+	}
+	end, err := time.Parse(nginxTimeLayout, f[3])
+	if err != nil {
+		return Entry{}, false
+	}
+	e := Entry{
+		Method:   req[0],
+		Bucket:   bucket,
+		Key:      key,
+		ClientIP: f[0],
+	}
+	e.Status, _ = strconv.Atoi(f[5])
+	e.Err = statusErr(e.Status)
+	if e.Method == http.MethodGet {
+		e.Size, _ = strconv.ParseInt(f[6], 10, 64)
+	}
+	if len(f) > 9 {
+		if secs, err := strconv.ParseFloat(f[9], 64); err == nil {
+			e.Latency = time.Duration(secs * float64(time.Second))
+		}
+	}
+	// nginx logs the time the request was completed.
+	e.Time = end.Add(-e.Latency)
+	return e, true
+}
+
+// splitPath splits a path style request path into bucket and key.
+func splitPath(path string) (bucket, key string) {
+	path = strings.TrimPrefix(path, "/")
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		return path[:i], path[i+1:]
+	}
+	return path, ""
+}
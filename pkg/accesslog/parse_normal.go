@@ -0,0 +1,82 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package accesslog
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// legacyTimeLayout is used by older warp access logs.
+const legacyTimeLayout = "2006-01-02T15:04:05"
+
+// parseNormal parses a line written by Writer.
+func parseNormal(line []byte) (Entry, bool) {
+	var rec struct {
+		Status   string          `json:"status"`
+		Action   string          `json:"action"`
+		Bucket   string          `json:"bucket"`
+		Object   string          `json:"object"`
+		Size     int64           `json:"size"`
+		Start    json.RawMessage `json:"start"`
+		Cost     float64         `json:"cost"`
+		Endpoint string          `json:"endpoint"`
+	}
+	if err := json.Unmarshal(line, &rec); err != nil || rec.Object == "" {
+		return Entry{}, false
+	}
+	e := Entry{
+		Method:   actionToMethod(rec.Action),
+		Bucket:   rec.Bucket,
+		Key:      rec.Object,
+		Size:     rec.Size,
+		Latency:  time.Duration(rec.Cost * float64(time.Millisecond)),
+		Err:      rec.Status != StatusSuccess,
+		Endpoint: rec.Endpoint,
+	}
+	if e.Method == "" {
+		return Entry{}, false
+	}
+	var start string
+	if len(rec.Start) > 0 && json.Unmarshal(rec.Start, &start) == nil {
+		if t, err := time.Parse(time.RFC3339Nano, start); err == nil {
+			e.Time = t
+		} else if t, err := time.ParseInLocation(legacyTimeLayout, start, time.Local); err == nil {
+			e.Time = t
+		}
+	}
+	return e, true
+}
+
+// actionToMethod converts a warp operation type to a HTTP method.
+// Logs without an action are assumed to be uploads.
+func actionToMethod(action string) string {
+	switch strings.ToUpper(action) {
+	case "", http.MethodPut:
+		return http.MethodPut
+	case http.MethodGet:
+		return http.MethodGet
+	case "STAT", http.MethodHead:
+		return http.MethodHead
+	case http.MethodDelete:
+		return http.MethodDelete
+	}
+	return ""
+}
@@ -0,0 +1,91 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package accesslog
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// s3TimeLayout is the time format used by S3 server access logs.
+const s3TimeLayout = "02/Jan/2006:15:04:05 -0700"
+
+// Field indexes of S3 server access logs.
+// See https://docs.aws.amazon.com/AmazonS3/latest/userguide/LogFormat.html
+const (
+	s3FieldBucket    = 1
+	s3FieldTime      = 2
+	s3FieldRemoteIP  = 3
+	s3FieldOperation = 6
+	s3FieldKey       = 7
+	s3FieldStatus    = 9
+	s3FieldBytesSent = 11
+	s3FieldObjSize   = 12
+	s3FieldTotalTime = 13
+	s3FieldHost      = 22
+)
+
+// parseS3 parses a line of an AWS S3 server access log.
+// Only REST object operations are returned.
+func parseS3(line []byte) (Entry, bool) {
+	f := splitFields(string(line))
+	if len(f) <= s3FieldTotalTime {
+		return Entry{}, false
+	}
+	// Operation is formatted as REST.<METHOD>.<RESOURCE>, e.g. REST.GET.OBJECT
+	op := strings.Split(f[s3FieldOperation], ".")
+	if len(op) != 3 || op[0] != "REST" || op[2] != "OBJECT" {
+		return Entry{}, false
+	}
+	if f[s3FieldKey] == "-" {
+		return Entry{}, false
+	}
+	key, err := url.PathUnescape(f[s3FieldKey])
+	if err != nil {
+		return Entry{}, false
+	}
+	t, err := time.Parse(s3TimeLayout, f[s3FieldTime])
+	if err != nil {
+		return Entry{}, false
+	}
+	e := Entry{
+		Time:     t,
+		Method:   op[1],
+		Bucket:   f[s3FieldBucket],
+		Key:      key,
+		ClientIP: f[s3FieldRemoteIP],
+	}
+	e.Status, _ = strconv.Atoi(f[s3FieldStatus])
+	e.Err = statusErr(e.Status)
+	if ms, err := strconv.ParseInt(f[s3FieldTotalTime], 10, 64); err == nil {
+		e.Latency = time.Duration(ms) * time.Millisecond
+	}
+	// Use bytes sent for downloads and the object size for everything else.
+	size := f[s3FieldObjSize]
+	if e.Method == http.MethodGet {
+		size = f[s3FieldBytesSent]
+	}
+	e.Size, _ = strconv.ParseInt(size, 10, 64)
+	if len(f) > s3FieldHost && f[s3FieldHost] != "-" {
+		e.Endpoint = f[s3FieldHost]
+	}
+	return e, true
+}
@@ -0,0 +1,63 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package accesslog
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseXStore parses a line of an xstore server access log.
+func parseXStore(line []byte) (Entry, bool) {
+	var rec struct {
+		DateTime   string          `json:"datetime"`
+		Code       int             `json:"code"`
+		Cost       json.RawMessage `json:"cost"`
+		Method     string          `json:"method"`
+		Bucket     string          `json:"bucket"`
+		Key        string          `json:"key"`
+		ObjectSize int64           `json:"object_size"`
+		ClientIP   string          `json:"client_ip"`
+		Hostname   string          `json:"hostname"`
+	}
+	if err := json.Unmarshal(line, &rec); err != nil || rec.Key == "" {
+		return Entry{}, false
+	}
+	end, err := time.ParseInLocation(legacyTimeLayout, rec.DateTime, time.Local)
+	if err != nil {
+		return Entry{}, false
+	}
+	// Cost is milliseconds, sent as either a string or a number.
+	cost := strings.Trim(string(rec.Cost), `"`)
+	ms, _ := strconv.ParseFloat(cost, 64)
+	latency := time.Duration(ms * float64(time.Millisecond))
+	return Entry{
+		Time:     end.Add(-latency),
+		Method:   strings.ToUpper(rec.Method),
+		Bucket:   rec.Bucket,
+		Key:      rec.Key,
+		Size:     rec.ObjectSize,
+		Latency:  latency,
+		Status:   rec.Code,
+		Err:      statusErr(rec.Code),
+		ClientIP: rec.ClientIP,
+		Endpoint: rec.Hostname,
+	}, true
+}
@@ -0,0 +1,209 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package accesslog
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Entry is a request read from an access log.
+type Entry struct {
+	// Time the request started.
+	Time time.Time
+	// HTTP method of the request.
+	Method string
+	Bucket string
+	Key    string
+	Size   int64
+	// Latency of the request, if known.
+	Latency time.Duration
+	// HTTP status code, 0 if unknown.
+	Status int
+	// Err is true if the request failed.
+	Err bool
+	// ClientIP is the address of the client, if known.
+	ClientIP string
+	// Endpoint is the server that handled the request, if known.
+	Endpoint string
+}
+
+// Entries is a slice of access log entries.
+type Entries []Entry
+
+// SortByTime will sort the entries by request start time.
+func (e Entries) SortByTime() {
+	sort.SliceStable(e, func(i, j int) bool {
+		return e[i].Time.Before(e[j].Time)
+	})
+}
+
+// Parser parses lines of an access log.
+type Parser interface {
+	// Parse returns the request logged on a line.
+	// ok should be false if the line does not contain an object request.
+	Parse(line []byte) (e Entry, ok bool)
+}
+
+// ParserFunc is an adapter to allow the use of ordinary functions as parsers.
+type ParserFunc func(line []byte) (Entry, bool)
+
+// Parse calls f(line).
+func (f ParserFunc) Parse(line []byte) (Entry, bool) {
+	return f(line)
+}
+
+var (
+	parsersMu sync.RWMutex
+	parsers   = make(map[string]Parser)
+)
+
+// RegisterParser makes a parser available for a log format.
+// If a parser is already registered for the format it is replaced.
+func RegisterParser(format string, p Parser) {
+	parsersMu.Lock()
+	parsers[format] = p
+	parsersMu.Unlock()
+}
+
+// GetParser returns the parser for a log format.
+func GetParser(format string) (Parser, error) {
+	parsersMu.RLock()
+	p, ok := parsers[format]
+	parsersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown access log format %q. Supported formats: %v", format, Formats())
+	}
+	return p, nil
+}
+
+// Formats returns the registered log formats, sorted by name.
+func Formats() []string {
+	parsersMu.RLock()
+	res := make([]string, 0, len(parsers))
+	for f := range parsers {
+		res = append(res, f)
+	}
+	parsersMu.RUnlock()
+	sort.Strings(res)
+	return res
+}
+
+func init() {
+	RegisterParser("normal", ParserFunc(parseNormal))
+	RegisterParser("xstore", ParserFunc(parseXStore))
+	RegisterParser("s3", ParserFunc(parseS3))
+	RegisterParser("minio", ParserFunc(parseMinioAudit))
+	RegisterParser("nginx", ParserFunc(parseNginx))
+}
+
+// ReadAll reads all entries of the specified format.
+// Lines that cannot be parsed are skipped.
+func ReadAll(r io.Reader, format string) (Entries, error) {
+	p, err := GetParser(format)
+	if err != nil {
+		return nil, err
+	}
+	var res Entries
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), 10<<20)
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if e, ok := p.Parse(line); ok {
+			res = append(res, e)
+		}
+	}
+	return res, sc.Err()
+}
+
+// ReadFile reads all entries of the specified format from a file.
+// Files ending with ".zst" are decompressed.
+func ReadFile(name, format string) (Entries, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if strings.HasSuffix(name, ".zst") {
+		dec, err := zstd.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer dec.Close()
+		r = dec
+	}
+	return ReadAll(r, format)
+}
+
+// statusErr returns whether a HTTP status code is an error.
+func statusErr(code int) bool {
+	return code < 200 || code >= 400
+}
+
+// splitFields splits a space separated log line.
+// Fields enclosed in double quotes or square brackets are kept as one field
+// with the enclosing characters removed.
+func splitFields(line string) []string {
+	var res []string
+	for {
+		line = strings.TrimLeft(line, " ")
+		if line == "" {
+			return res
+		}
+		var end byte = ' '
+		switch line[0] {
+		case '"':
+			end = '"'
+		case '[':
+			end = ']'
+		}
+		if end != ' ' {
+			line = line[1:]
+		}
+		i := strings.IndexByte(line, end)
+		if end == '"' {
+			// Skip escaped quotes.
+			for i > 0 && line[i-1] == '\\' {
+				j := strings.IndexByte(line[i+1:], '"')
+				if j < 0 {
+					i = -1
+					break
+				}
+				i += j + 1
+			}
+		}
+		if i < 0 {
+			return append(res, line)
+		}
+		res = append(res, line[:i])
+		line = line[i+1:]
+	}
+}
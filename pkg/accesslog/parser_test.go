@@ -0,0 +1,139 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package accesslog
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadAll(t *testing.T) {
+	tests := []struct {
+		format string
+		log    string
+		want   Entry
+	}{
+		{
+			format: "normal",
+			log:    `{"status":"succ","action":"get","bucket":"b","object":"k/1.csv","size":1024,"start":"2021-06-09T10:15:59Z","cost":12.5}`,
+			want: Entry{
+				Time:    time.Date(2021, 6, 9, 10, 15, 59, 0, time.UTC),
+				Method:  "GET",
+				Bucket:  "b",
+				Key:     "k/1.csv",
+				Size:    1024,
+				Latency: 12500 * time.Microsecond,
+			},
+		},
+		{
+			format: "xstore",
+			log:    `{"hostname":"cn-test-1.xstore.qihoo.net","datetime":"2021-06-09T18:15:59","code":200,"cost":"3029","client_ip":"10.18.22.17","bucket":"test01","method":"PUT","key":"chinadaily1.txt","object_size":270}`,
+			want: Entry{
+				Time:     time.Date(2021, 6, 9, 18, 15, 59, 0, time.Local).Add(-3029 * time.Millisecond),
+				Method:   "PUT",
+				Bucket:   "test01",
+				Key:      "chinadaily1.txt",
+				Size:     270,
+				Latency:  3029 * time.Millisecond,
+				Status:   200,
+				ClientIP: "10.18.22.17",
+				Endpoint: "cn-test-1.xstore.qihoo.net",
+			},
+		},
+		{
+			format: "s3",
+			log:    `79a59df900b9 awsexamplebucket1 [06/Feb/2019:00:00:38 +0000] 192.0.2.3 79a59df900b9 3E57427F3EXAMPLE REST.GET.OBJECT photos/2019/08/puppy.jpg "GET /awsexamplebucket1/photos/2019/08/puppy.jpg?x-foo=bar HTTP/1.1" 200 - 2662992 3462992 70 10 "-" "S3Console/0.4" - s9lzHYrFp76ZVxRcpX9+5cjAnEH2ROuNkd2BHfIa6UkFVdtjf5mKR3/eTPFvsiP/XV/VLi31234= SigV4 ECDHE-RSA-AES128-GCM-SHA256 AuthHeader awsexamplebucket1.s3.us-west-1.amazonaws.com TLSV1.1`,
+			want: Entry{
+				Time:     time.Date(2019, 2, 6, 0, 0, 38, 0, time.UTC),
+				Method:   "GET",
+				Bucket:   "awsexamplebucket1",
+				Key:      "photos/2019/08/puppy.jpg",
+				Size:     2662992,
+				Latency:  70 * time.Millisecond,
+				Status:   200,
+				ClientIP: "192.0.2.3",
+				Endpoint: "awsexamplebucket1.s3.us-west-1.amazonaws.com",
+			},
+		},
+		{
+			format: "minio",
+			log:    `{"version":"1","time":"2021-06-09T10:15:59.5Z","trigger":"incoming","api":{"name":"HeadObject","bucket":"test","object":"a/b.txt","status":"Not Found","statusCode":404,"timeToResponse":"500ms","rx":0,"tx":0},"remotehost":"127.0.0.1","requestHeader":{"Host":"minio1:9000"}}`,
+			want: Entry{
+				Time:     time.Date(2021, 6, 9, 10, 15, 59, 0, time.UTC),
+				Method:   "HEAD",
+				Bucket:   "test",
+				Key:      "a/b.txt",
+				Latency:  500 * time.Millisecond,
+				Status:   404,
+				Err:      true,
+				ClientIP: "127.0.0.1",
+				Endpoint: "minio1:9000",
+			},
+		},
+		{
+			format: "nginx",
+			log:    `10.0.0.1 - - [09/Jun/2021:10:16:00 +0000] "DELETE /bucket/dir/obj%201 HTTP/1.1" 204 0 "-" "MinIO (linux; amd64) minio-go/v7.0.10" 1.000`,
+			want: Entry{
+				Time:     time.Date(2021, 6, 9, 10, 15, 59, 0, time.UTC),
+				Method:   "DELETE",
+				Bucket:   "bucket",
+				Key:      "dir/obj 1",
+				Latency:  time.Second,
+				Status:   204,
+				ClientIP: "10.0.0.1",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			// Add a line that should be skipped.
+			entries, err := ReadAll(strings.NewReader("not a request\n"+test.log+"\n"), test.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 {
+				t.Fatalf("want 1 entry, got %d", len(entries))
+			}
+			got := entries[0]
+			if !got.Time.Equal(test.want.Time) {
+				t.Errorf("time: want %v, got %v", test.want.Time, got.Time)
+			}
+			got.Time = test.want.Time
+			if got != test.want {
+				t.Errorf("want %+v, got %+v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestGetParser(t *testing.T) {
+	if _, err := GetParser("unknown"); err == nil {
+		t.Fatal("want error for unknown format")
+	}
+	RegisterParser("test", ParserFunc(func(line []byte) (Entry, bool) {
+		return Entry{Method: "GET", Key: string(line)}, true
+	}))
+	entries, err := ReadAll(strings.NewReader("a\nb\n"), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[1].Key != "b" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+}
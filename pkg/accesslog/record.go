@@ -0,0 +1,55 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package accesslog
+
+import (
+	"time"
+)
+
+const (
+	// StatusSuccess is the status of a request that completed without error.
+	StatusSuccess = "succ"
+	// StatusError is the status of a request that failed.
+	StatusError = "err"
+)
+
+// Record is a single request in the access log.
+// Each record is written as a single line of JSON.
+type Record struct {
+	// Status is either StatusSuccess or StatusError.
+	Status string `json:"status"`
+	// Action is the lower case operation type, for instance "put" or "stat".
+	Action    string `json:"action"`
+	Bucket    string `json:"bucket"`
+	Object    string `json:"object"`
+	VersionID string `json:"version_id,omitempty"`
+	Size      int64  `json:"size"`
+	// Objects is the number of objects in the request, for instance a delete batch.
+	Objects  int    `json:"objects"`
+	ETag     string `json:"etag"`
+	Endpoint string `json:"endpoint,omitempty"`
+	Thread   uint16 `json:"thread"`
+	// Start time of the request.
+	Start time.Time `json:"start"`
+	// Cost is the request duration in milliseconds.
+	Cost float64 `json:"cost"`
+	// Slow is set if the request violated its latency objective.
+	Slow bool `json:"slow"`
+	// Msg contains the error message, if any.
+	Msg string `json:"msg"`
+}
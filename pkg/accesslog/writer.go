@@ -0,0 +1,318 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package accesslog
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Options controls how the access log is written and rotated.
+type Options struct {
+	// Path of the active log file.
+	Path string
+
+	// MaxSize is the number of bytes after which the log is rotated.
+	// Size based rotation is disabled when this is <= 0.
+	MaxSize int64
+
+	// MaxAge is the time after which the log is rotated.
+	// Time based rotation is disabled when this is <= 0.
+	MaxAge time.Duration
+
+	// Compress rotated logs with zstd.
+	// The active log is always written uncompressed.
+	Compress bool
+
+	// QueueSize is the number of records that can be queued
+	// before Write blocks. Records are never dropped.
+	QueueSize int
+
+	// BufferSize is the size of the file write buffer.
+	BufferSize int
+
+	// OnError is called with errors that occur in the background,
+	// like failing to compress a rotated log. Can be nil.
+	// These errors are also returned by Close.
+	OnError func(err error)
+}
+
+// DefaultOptions returns the default options for writing to the specified path.
+func DefaultOptions(path string) Options {
+	return Options{
+		Path:       path,
+		QueueSize:  10000,
+		BufferSize: 1 << 20,
+	}
+}
+
+// Writer writes access log records from any number of goroutines.
+// Records are serialized by a single goroutine,
+// so lines will never be interleaved.
+type Writer struct {
+	opts  Options
+	queue chan Record
+
+	// mu protects closed and sending on queue.
+	mu     sync.RWMutex
+	closed bool
+
+	done       chan struct{}
+	compressWg sync.WaitGroup
+
+	// compressMu protects compressErr.
+	compressMu  sync.Mutex
+	compressErr error
+
+	// Owned by the writer goroutine until done is closed.
+	f      *os.File
+	bw     *bufio.Writer
+	size   int64
+	opened time.Time
+	err    error
+}
+
+// errClosed is returned when writing to a closed log.
+var errClosed = errors.New("accesslog: writer closed")
+
+// New creates the log at the path given in the options
+// and starts the writer.
+func New(opts Options) (*Writer, error) {
+	if opts.Path == "" {
+		return nil, errors.New("accesslog: no path specified")
+	}
+	def := DefaultOptions(opts.Path)
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = def.QueueSize
+	}
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = def.BufferSize
+	}
+	w := &Writer{
+		opts:  opts,
+		queue: make(chan Record, opts.QueueSize),
+		done:  make(chan struct{}),
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	go w.run()
+	return w, nil
+}
+
+// Write queues a record for writing.
+// Write will block if the queue is full.
+func (w *Writer) Write(r Record) error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return errClosed
+	}
+	w.queue <- r
+	return nil
+}
+
+// Close will write all queued records, flush the log and close it.
+// It is safe to call Close multiple times.
+// The first error encountered while writing is returned.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+	<-w.done
+	if w.err != nil {
+		return w.err
+	}
+	w.compressMu.Lock()
+	defer w.compressMu.Unlock()
+	return w.compressErr
+}
+
+func (w *Writer) run() {
+	defer close(w.done)
+	enc := json.NewEncoder(writeCounter{w: w})
+	flush := time.NewTicker(time.Second)
+	defer flush.Stop()
+	for {
+		select {
+		case r, ok := <-w.queue:
+			if !ok {
+				w.setErr(w.closeFile())
+				w.compressWg.Wait()
+				return
+			}
+			if w.shouldRotate() {
+				w.setErr(w.rotate())
+			}
+			if w.bw == nil {
+				// Could not reopen the log after rotating.
+				continue
+			}
+			w.setErr(enc.Encode(r))
+		case <-flush.C:
+			if w.bw != nil {
+				w.setErr(w.bw.Flush())
+			}
+		}
+	}
+}
+
+// setErr keeps the first error.
+func (w *Writer) setErr(err error) {
+	if err != nil && w.err == nil {
+		w.err = err
+	}
+}
+
+func (w *Writer) shouldRotate() bool {
+	if w.opts.MaxSize > 0 && w.size >= w.opts.MaxSize {
+		return true
+	}
+	return w.opts.MaxAge > 0 && time.Since(w.opened) >= w.opts.MaxAge
+}
+
+func (w *Writer) open() error {
+	f, err := os.Create(w.opts.Path)
+	if err != nil {
+		return err
+	}
+	w.f = f
+	w.bw = bufio.NewWriterSize(f, w.opts.BufferSize)
+	w.size = 0
+	w.opened = time.Now()
+	return nil
+}
+
+func (w *Writer) closeFile() error {
+	if w.f == nil {
+		return nil
+	}
+	err := w.bw.Flush()
+	if err2 := w.f.Close(); err == nil {
+		err = err2
+	}
+	w.f, w.bw = nil, nil
+	return err
+}
+
+// rotate will move the current log to a timestamped name and open a new log.
+func (w *Writer) rotate() error {
+	if err := w.closeFile(); err != nil {
+		return err
+	}
+	name := backupName(w.opts.Path, time.Now(), w.opts.Compress)
+	if err := os.Rename(w.opts.Path, name); err != nil {
+		return err
+	}
+	if w.opts.Compress {
+		w.compressWg.Add(1)
+		go func() {
+			defer w.compressWg.Done()
+			if err := compressFile(name); err != nil {
+				w.compressFailed(fmt.Errorf("accesslog: unable to compress %s: %w", name, err))
+			}
+		}()
+	}
+	return w.open()
+}
+
+// compressFailed reports an error compressing a rotated log.
+func (w *Writer) compressFailed(err error) {
+	w.compressMu.Lock()
+	if w.compressErr == nil {
+		w.compressErr = err
+	}
+	w.compressMu.Unlock()
+	if w.opts.OnError != nil {
+		w.opts.OnError(err)
+	}
+}
+
+// backupName returns an unused name for a rotated log.
+// The timestamp is inserted before the extension.
+// If several rotations happen within the same millisecond a counter is added.
+func backupName(path string, t time.Time, compressed bool) string {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	stamp := t.Format("2006-01-02T15-04-05.000")
+	name := fmt.Sprintf("%s-%s%s", base, stamp, ext)
+	for i := 1; exists(name) || (compressed && exists(name+".zst")); i++ {
+		name = fmt.Sprintf("%s-%s.%d%s", base, stamp, i, ext)
+	}
+	return name
+}
+
+func exists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
+
+// compressFile compresses the file to name+".zst" and removes the original.
+func compressFile(name string) error {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.Create(name + ".zst")
+	if err != nil {
+		return err
+	}
+	enc, err := zstd.NewWriter(dst)
+	if err != nil {
+		dst.Close()
+		return err
+	}
+	_, err = io.Copy(enc, src)
+	if err2 := enc.Close(); err == nil {
+		err = err2
+	}
+	if err2 := dst.Close(); err == nil {
+		err = err2
+	}
+	if err != nil {
+		os.Remove(name + ".zst")
+		return err
+	}
+	src.Close()
+	return os.Remove(name)
+}
+
+// writeCounter writes to the buffered file and keeps track of the size.
+type writeCounter struct {
+	w *Writer
+}
+
+func (c writeCounter) Write(p []byte) (int, error) {
+	n, err := c.w.bw.Write(p)
+	c.w.size += int64(n)
+	return n, err
+}
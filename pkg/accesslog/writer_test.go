@@ -0,0 +1,106 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package accesslog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestWriter_Rotate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "accesslog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	opts := DefaultOptions(filepath.Join(dir, "access.log"))
+	opts.MaxSize = 10 << 10
+	opts.Compress = true
+	w, err := New(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const goroutines, perG = 10, 500
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perG; j++ {
+				err := w.Write(Record{Status: StatusSuccess, Action: "put", Bucket: "bucket", Object: strings.Repeat("x", j%100), Size: int64(j)})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write(Record{}); err == nil {
+		t.Fatal("expected error writing to closed log")
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) < 2 {
+		t.Fatalf("expected log to be rotated, got %v", files)
+	}
+	dec, _ := zstd.NewReader(nil)
+	defer dec.Close()
+	var lines int
+	for _, file := range files {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.HasSuffix(file, ".zst") {
+			b, err = dec.DecodeAll(b, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+		} else if file != opts.Path {
+			t.Errorf("rotated log %q was not compressed", file)
+		}
+		sc := bufio.NewScanner(bytes.NewReader(b))
+		for sc.Scan() {
+			var r Record
+			if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+				t.Fatalf("%s: line %q: %v", file, sc.Text(), err)
+			}
+			lines++
+		}
+	}
+	if lines != goroutines*perG {
+		t.Errorf("want %d records, got %d", goroutines*perG, lines)
+	}
+}
@@ -63,10 +63,24 @@ type Operation struct {
 	Errors int `json:"errors"`
 	// Subset of errors.
 	FirstErrors []string `json:"first_errors"`
+	// Downloads that returned data not matching the uploaded object.
+	CorruptReads int `json:"corrupt_reads,omitempty"`
+	// Downloads that returned data from another version of the object.
+	StaleReads int `json:"stale_reads,omitempty"`
+	// Reads and listings that did not find the latest version of the object.
+	MissingReads int `json:"missing_reads,omitempty"`
+	// Visibility delay, if consistency was checked.
+	Visibility *Visibility `json:"visibility,omitempty"`
 	// Throughput information.
 	Throughput Throughput `json:"throughput"`
 	// Throughput by host.
 	ThroughputByHost map[string]Throughput `json:"throughput_by_host"`
+	// Throughput by bucket, if operations were spread across several buckets.
+	ThroughputByBucket map[string]Throughput `json:"throughput_by_bucket,omitempty"`
+	// Statistics by prefix, if operations were spread across several prefixes.
+	ByPrefix map[string]Prefix `json:"by_prefix,omitempty"`
+	// SLO statistics, if operations were checked against latency objectives.
+	SLO *SLO `json:"slo,omitempty"`
 }
 
 // SegmentDurFn accepts a total time and should return the duration used for each segment.
@@ -76,6 +90,8 @@ type Options struct {
 	Prefiltered bool
 	DurFunc     SegmentDurFn
 	SkipDur     time.Duration
+	// SizeBounds will split requests of different sizes at these sizes, if set.
+	SizeBounds []int64
 }
 
 // Aggregate returns statistics when only a single operation was running concurrently.
@@ -111,6 +127,9 @@ func Aggregate(o bench.Operations, opts Options) Aggregated {
 			}
 			ops = o.FilterSuccessful()
 		}
+		// Sequences and delays are made of other operations and are not counted twice.
+		ops = ops.FilterRequests()
+		errs = errs.FilterRequests()
 
 		total := ops.Total(false)
 		total.Errors = len(errs)
@@ -180,14 +199,40 @@ func Aggregate(o bench.Operations, opts Options) Aggregated {
 			if len(errs) > 0 {
 				a.Errors = len(errs)
 				for _, err := range errs {
+					switch err.ErrKind {
+					case bench.ErrKindCorrupt:
+						a.CorruptReads++
+					case bench.ErrKindStale:
+						a.StaleReads++
+					case bench.ErrKindMissing:
+						a.MissingReads++
+					}
 					if len(a.FirstErrors) >= 10 {
-						break
+						continue
 					}
 					a.FirstErrors = append(a.FirstErrors, fmt.Sprintf("%s, %s: %v", err.Endpoint, err.End.Round(time.Second), err.Err))
 				}
 			}
 
 			segmentDur := opts.DurFunc(ops.Duration())
+			a.SLO = newSLO(ops, segmentDur)
+			if bench.IsDelayOp(typ) {
+				// Delays are not requests, so they have no throughput.
+				a.Visibility = newVisibility(ops, segmentDur)
+				a.N = len(ops)
+				a.StartTime, a.EndTime = ops.TimeRange()
+				a.Throughput = Throughput{
+					Operations:            len(ops) - len(errs),
+					Errors:                len(errs),
+					StartTime:             a.StartTime,
+					EndTime:               a.EndTime,
+					MeasureDurationMillis: durToMillis(a.EndTime.Sub(a.StartTime)),
+				}
+				a.Concurrency = ops.Threads()
+				a.Clients = ops.Clients()
+				a.Hosts = ops.Hosts()
+				return
+			}
 
 			sopts := bench.SegmentOptions{
 				From:           time.Time{},
@@ -230,7 +275,7 @@ func Aggregate(o bench.Operations, opts Options) Aggregated {
 			if !ops.MultipleSizes() {
 				a.SingleSizedRequests = RequestAnalysisSingleSized(ops, !opts.Prefiltered)
 			} else {
-				a.MultiSizedRequests = RequestAnalysisMultiSized(ops, !opts.Prefiltered)
+				a.MultiSizedRequests = RequestAnalysisMultiSized(ops, !opts.Prefiltered, opts.SizeBounds)
 			}
 
 			eps := ops.Endpoints()
@@ -273,9 +318,36 @@ func Aggregate(o bench.Operations, opts Options) Aggregated {
 				}(ep)
 			}
 			epWg.Wait()
+			a.ThroughputByBucket = throughputByBucket(allOps)
+			a.ByPrefix = byPrefix(allOps)
 		}(i)
 	}
 	wg.Wait()
 	a.Operations = res
 	return a
 }
+
+// throughputByBucket returns the throughput of each bucket,
+// or nil if operations were not run against several buckets.
+func throughputByBucket(ops bench.Operations) map[string]Throughput {
+	buckets := ops.Buckets()
+	if len(buckets) <= 1 {
+		return nil
+	}
+	res := make(map[string]Throughput, len(buckets))
+	for _, bucket := range buckets {
+		ops := ops.FilterByBucket(bucket)
+		errs := ops.FilterErrors()
+		if len(errs) > 0 {
+			ops = ops.FilterSuccessful()
+		}
+		var t Throughput
+		if len(ops) > 0 {
+			total := ops.Total(false)
+			t.fill(total)
+		}
+		t.Errors = len(errs)
+		res[bucket] = t
+	}
+	return res
+}
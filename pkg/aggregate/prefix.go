@@ -0,0 +1,67 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package aggregate
+
+import (
+	"github.com/minio/warp/pkg/bench"
+)
+
+// Prefix contains statistics of operations on objects with the same prefix.
+type Prefix struct {
+	// Throughput of the prefix.
+	Throughput Throughput `json:"throughput"`
+	// Average request duration.
+	DurAvgMillis int `json:"dur_avg_millis"`
+	// Median request duration.
+	DurMedianMillis int `json:"dur_median_millis"`
+	// 90% request time.
+	Dur90Millis int `json:"dur_90_millis"`
+	// 99% request time.
+	Dur99Millis int `json:"dur_99_millis"`
+	// Slowest request time.
+	SlowestMillis int `json:"slowest_millis"`
+}
+
+// byPrefix returns statistics for each prefix,
+// or nil if operations were not run on several prefixes.
+func byPrefix(ops bench.Operations) map[string]Prefix {
+	prefixes := ops.ByPrefix()
+	if len(prefixes) <= 1 {
+		return nil
+	}
+	res := make(map[string]Prefix, len(prefixes))
+	for prefix, ops := range prefixes {
+		errs := ops.FilterErrors()
+		if len(errs) > 0 {
+			ops = ops.FilterSuccessful()
+		}
+		var p Prefix
+		if len(ops) > 0 {
+			p.Throughput.fill(ops.Total(false))
+			ops.SortByDuration()
+			p.DurAvgMillis = durToMillis(ops.AvgDuration())
+			p.DurMedianMillis = durToMillis(ops.Median(0.5).Duration())
+			p.Dur90Millis = durToMillis(ops.Median(0.9).Duration())
+			p.Dur99Millis = durToMillis(ops.Median(0.99).Duration())
+			p.SlowestMillis = durToMillis(ops.Median(1).Duration())
+		}
+		p.Throughput.Errors = len(errs)
+		res[prefix] = p
+	}
+	return res
+}
@@ -150,7 +150,7 @@ type MultiSizedRequests struct {
 	ByHost map[string]RequestSizeRange `json:"by_host,omitempty"`
 }
 
-func (a *MultiSizedRequests) fill(ops bench.Operations) {
+func (a *MultiSizedRequests) fill(ops bench.Operations, sizeBounds []int64) {
 	start, end := ops.TimeRange()
 	a.Requests = len(ops)
 	if len(ops) == 0 {
@@ -159,6 +159,9 @@ func (a *MultiSizedRequests) fill(ops bench.Operations) {
 	}
 	a.AvgObjSize = ops.AvgSize()
 	sizes := ops.SplitSizes(0.05)
+	if len(sizeBounds) > 0 {
+		sizes = ops.SplitSizesAt(sizeBounds)
+	}
 	a.BySize = make([]RequestSizeRange, len(sizes))
 	var wg sync.WaitGroup
 	wg.Add(len(sizes))
@@ -222,7 +225,9 @@ func RequestAnalysisHostsSingleSized(o bench.Operations) map[string]SingleSizedR
 }
 
 // RequestAnalysisMultiSized performs analysis where objects have different sizes.
-func RequestAnalysisMultiSized(o bench.Operations, allThreads bool) *MultiSizedRequests {
+// If size boundaries are specified requests are split at them,
+// otherwise sizes are split in powers of 10.
+func RequestAnalysisMultiSized(o bench.Operations, allThreads bool, sizeBounds []int64) *MultiSizedRequests {
 	var res MultiSizedRequests
 	// Single type, require one operation per thread.
 	start, end := o.ActiveTimeRange(allThreads)
@@ -233,7 +238,7 @@ func RequestAnalysisMultiSized(o bench.Operations, allThreads bool) *MultiSizedR
 		res.Skipped = true
 		return &res
 	}
-	res.fill(active)
+	res.fill(active, sizeBounds)
 	res.ByHost = RequestAnalysisHostsMultiSized(active)
 	return &res
 }
@@ -0,0 +1,108 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package aggregate
+
+import (
+	"time"
+
+	"github.com/minio/warp/pkg/bench"
+)
+
+// SLO contains statistics of operations checked against latency objectives.
+type SLO struct {
+	SLOCount
+	// ByHost contains the statistics of each host.
+	ByHost map[string]SLOCount `json:"by_host,omitempty"`
+	// SegmentDurationMillis is the duration of each segment.
+	SegmentDurationMillis int `json:"segment_duration_millis,omitempty"`
+	// Segments contains the statistics of each time segment, ordered by time.
+	Segments []SLOSegment `json:"segments,omitempty"`
+}
+
+// SLOCount contains the number of operations that met or violated their objective.
+type SLOCount struct {
+	// Checked is the number of operations with an objective.
+	Checked int `json:"checked"`
+	// Violated is the number of operations that violated their objective.
+	Violated int `json:"violated"`
+}
+
+// SLOSegment contains the statistics of a time segment.
+type SLOSegment struct {
+	Start time.Time `json:"start"`
+	SLOCount
+}
+
+// ViolationRatio returns the ratio of checked operations that violated their objective.
+func (s SLOCount) ViolationRatio() float64 {
+	if s.Checked == 0 {
+		return 0
+	}
+	return float64(s.Violated) / float64(s.Checked)
+}
+
+func (s *SLOCount) add(v bench.SLOVerdict) {
+	switch v {
+	case bench.SLOMet:
+		s.Checked++
+	case bench.SLOViolated:
+		s.Checked++
+		s.Violated++
+	}
+}
+
+// newSLO returns the SLO statistics of the operations.
+// Operations are placed in segments by their start time.
+// If no operations have been checked nil is returned.
+func newSLO(ops bench.Operations, segDur time.Duration) *SLO {
+	var res SLO
+	var start time.Time
+	for _, op := range ops {
+		if op.SLO == bench.SLONone {
+			continue
+		}
+		if start.IsZero() || op.Start.Before(start) {
+			start = op.Start
+		}
+	}
+	if start.IsZero() {
+		return nil
+	}
+	res.ByHost = make(map[string]SLOCount)
+	if segDur > 0 {
+		res.SegmentDurationMillis = durToMillis(segDur)
+	}
+	for _, op := range ops {
+		if op.SLO == bench.SLONone {
+			continue
+		}
+		res.add(op.SLO)
+		host := res.ByHost[op.Endpoint]
+		host.add(op.SLO)
+		res.ByHost[op.Endpoint] = host
+		if segDur <= 0 {
+			continue
+		}
+		idx := int(op.Start.Sub(start) / segDur)
+		for len(res.Segments) <= idx {
+			res.Segments = append(res.Segments, SLOSegment{Start: start.Add(time.Duration(len(res.Segments)) * segDur)})
+		}
+		res.Segments[idx].add(op.SLO)
+	}
+	return &res
+}
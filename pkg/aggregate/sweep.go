@@ -0,0 +1,108 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package aggregate
+
+import (
+	"time"
+
+	"github.com/minio/warp/pkg/bench"
+)
+
+// Sweep contains statistics for each step of a sweep.
+type Sweep struct {
+	// Steps contains the statistics of each step and operation type, in the order they were run.
+	Steps []SweepStep `json:"steps"`
+	// Knee contains the step where latency starts growing faster than throughput
+	// by operation type. Missing if it was not reached.
+	Knee map[string]string `json:"knee,omitempty"`
+}
+
+// SweepStep contains statistics for a single operation type in a sweep step.
+type SweepStep struct {
+	// Step name.
+	Step string `json:"step"`
+	// Operation type.
+	Type string `json:"type"`
+	// Throughput of successful operations.
+	Throughput Throughput `json:"throughput"`
+	// Total number of successful requests.
+	Requests int `json:"requests"`
+	// Average request duration.
+	DurAvgMillis int `json:"dur_avg_millis"`
+	// Median request duration.
+	DurMedianMillis int `json:"dur_median_millis"`
+	// 90% request time.
+	Dur90Millis int `json:"dur_90_millis"`
+	// 99% request time.
+	Dur99Millis int `json:"dur_99_millis"`
+
+	durMedian time.Duration
+}
+
+// SweepAnalysis returns statistics for each sweep step of the operations.
+// Operations without a step are ignored.
+func SweepAnalysis(o bench.Operations) Sweep {
+	var res Sweep
+	steps := o.Steps()
+	stepped := make(bench.Operations, 0, len(o))
+	for _, op := range o {
+		if op.Step != "" {
+			stepped = append(stepped, op)
+		}
+	}
+	for _, typ := range stepped.OpTypes() {
+		ops := stepped.FilterByOp(typ)
+		for i, step := range steps {
+			s := SweepStep{Step: step, Type: typ}
+			ops := ops.FilterByStep(step)
+			errs := ops.FilterErrors()
+			ops = ops.FilterSuccessful()
+			if len(ops) > 0 {
+				s.Throughput.fill(ops.Total(false))
+				ops.SortByDuration()
+				s.Requests = len(ops)
+				s.durMedian = ops.Median(0.5).Duration()
+				s.DurAvgMillis = durToMillis(ops.AvgDuration())
+				s.DurMedianMillis = durToMillis(s.durMedian)
+				s.Dur90Millis = durToMillis(ops.Median(0.9).Duration())
+				s.Dur99Millis = durToMillis(ops.Median(0.99).Duration())
+			}
+			s.Throughput.Errors = len(errs)
+			if i > 0 && res.Knee[typ] == "" {
+				if prev := res.Steps[len(res.Steps)-1]; s.kneeAfter(prev) {
+					if res.Knee == nil {
+						res.Knee = make(map[string]string)
+					}
+					res.Knee[typ] = prev.Step
+				}
+			}
+			res.Steps = append(res.Steps, s)
+		}
+	}
+	return res
+}
+
+// kneeAfter returns whether latency grew faster than throughput since the previous step.
+func (s SweepStep) kneeAfter(prev SweepStep) bool {
+	if prev.durMedian <= 0 || prev.Throughput.AverageOPS <= 0 {
+		return false
+	}
+	latency := float64(s.durMedian) / float64(prev.durMedian)
+	throughput := s.Throughput.AverageOPS / prev.Throughput.AverageOPS
+	return latency > throughput
+}
@@ -0,0 +1,96 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package aggregate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/minio/warp/pkg/bench"
+)
+
+func TestSweepAnalysis(t *testing.T) {
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var ops bench.Operations
+	// addStep adds n operations of the specified duration evenly spread over a second.
+	addStep := func(step string, n int, dur time.Duration) {
+		start := base
+		for i := 0; i < n; i++ {
+			opStart := start.Add(time.Duration(i) * time.Second / time.Duration(n))
+			ops = append(ops, bench.Operation{
+				OpType:   "GET",
+				Thread:   uint16(i),
+				Size:     1 << 10,
+				ObjPerOp: 1,
+				Start:    opStart,
+				End:      opStart.Add(dur),
+				Step:     step,
+			})
+		}
+		base = base.Add(2 * time.Second)
+	}
+	addStep("c=1", 10, 10*time.Millisecond)
+	// Throughput doubles, latency grows less.
+	addStep("c=2", 20, 12*time.Millisecond)
+	// Throughput barely grows, latency quadruples.
+	addStep("c=4", 21, 50*time.Millisecond)
+	// Operations outside a sweep are ignored.
+	ops = append(ops, bench.Operation{OpType: "PUT", Start: base, End: base.Add(time.Millisecond)})
+
+	got := SweepAnalysis(ops)
+	if len(got.Steps) != 3 {
+		t.Fatalf("got %d steps, want 3: %+v", len(got.Steps), got.Steps)
+	}
+	for i, want := range []struct {
+		step     string
+		requests int
+		median   int
+	}{{"c=1", 10, 10}, {"c=2", 20, 12}, {"c=4", 21, 50}} {
+		s := got.Steps[i]
+		if s.Step != want.step || s.Type != "GET" || s.Requests != want.requests || s.DurMedianMillis != want.median {
+			t.Errorf("step %d: got %s %s, %d requests, median %dms, want %s GET, %d requests, median %dms",
+				i, s.Step, s.Type, s.Requests, s.DurMedianMillis, want.step, want.requests, want.median)
+		}
+	}
+	if knee := got.Knee["GET"]; knee != "c=2" {
+		t.Errorf("got knee %q, want %q", knee, "c=2")
+	}
+}
+
+func TestSweepStepKneeAfter(t *testing.T) {
+	step := func(median time.Duration, ops float64) SweepStep {
+		return SweepStep{durMedian: median, Throughput: Throughput{AverageOPS: ops}}
+	}
+	tests := []struct {
+		name      string
+		prev, cur SweepStep
+		want      bool
+	}{
+		{name: "scaling", prev: step(10*time.Millisecond, 100), cur: step(11*time.Millisecond, 190), want: false},
+		{name: "saturated", prev: step(10*time.Millisecond, 100), cur: step(20*time.Millisecond, 110), want: true},
+		{name: "no-latency", prev: step(0, 100), cur: step(20*time.Millisecond, 110), want: false},
+		{name: "no-throughput", prev: step(10*time.Millisecond, 0), cur: step(20*time.Millisecond, 110), want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.cur.kneeAfter(test.prev); got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package aggregate
+
+import (
+	"time"
+
+	"github.com/minio/warp/pkg/bench"
+)
+
+// Visibility contains the delay before writes were observed,
+// either by consistency checks or on a replication target.
+type Visibility struct {
+	VisibilityDelay
+	// Delay by time of write, if segmented.
+	SegmentDurationMillis int                 `json:"segment_duration_millis,omitempty"`
+	Segments              []VisibilitySegment `json:"segments,omitempty"`
+}
+
+// VisibilityDelay contains delay statistics.
+type VisibilityDelay struct {
+	// Writes that were observed.
+	Delayed int `json:"delayed"`
+	// Writes that were not observed before the timeout.
+	TimedOut int `json:"timed_out"`
+	// Average delay.
+	DelayAvgMillis int `json:"delay_avg_millis"`
+	// Median delay.
+	DelayMedianMillis int `json:"delay_median_millis"`
+	// 90% delay.
+	Delay90Millis int `json:"delay_90_millis"`
+	// 99% delay.
+	Delay99Millis int `json:"delay_99_millis"`
+	// Longest delay.
+	DelayMaxMillis int `json:"delay_max_millis"`
+}
+
+// VisibilitySegment contains the delay of writes made in a time segment.
+type VisibilitySegment struct {
+	Start time.Time `json:"start"`
+	VisibilityDelay
+}
+
+// newVisibility returns the delay of operations where bench.IsDelayOp is true.
+// The operations start when the write ended.
+// If segDur is > 0 the delay is also segmented by time of write.
+func newVisibility(ops bench.Operations, segDur time.Duration) *Visibility {
+	var res Visibility
+	res.fill(ops)
+	if segDur <= 0 || len(ops) == 0 {
+		return &res
+	}
+	res.SegmentDurationMillis = durToMillis(segDur)
+	start, _ := ops.TimeRange()
+	var segs []bench.Operations
+	for _, op := range ops {
+		idx := int(op.Start.Sub(start) / segDur)
+		for len(segs) <= idx {
+			segs = append(segs, nil)
+		}
+		segs[idx] = append(segs[idx], op)
+	}
+	for i, seg := range segs {
+		s := VisibilitySegment{Start: start.Add(time.Duration(i) * segDur)}
+		s.fill(seg)
+		res.Segments = append(res.Segments, s)
+	}
+	return &res
+}
+
+func (v *VisibilityDelay) fill(ops bench.Operations) {
+	v.TimedOut = len(ops.FilterErrors())
+	// Copy, so the order of ops is kept.
+	ops = append(bench.Operations(nil), ops.FilterSuccessful()...)
+	v.Delayed = len(ops)
+	if len(ops) == 0 {
+		return
+	}
+	ops.SortByDuration()
+	v.DelayAvgMillis = durToMillis(ops.AvgDuration())
+	v.DelayMedianMillis = durToMillis(ops.Median(0.5).Duration())
+	v.Delay90Millis = durToMillis(ops.Median(0.9).Duration())
+	v.Delay99Millis = durToMillis(ops.Median(0.99).Duration())
+	v.DelayMaxMillis = durToMillis(ops[len(ops)-1].Duration())
+}
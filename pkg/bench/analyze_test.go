@@ -70,3 +70,48 @@ func TestOperations_Segment(t *testing.T) {
 		t.Log(buf.String())
 	}
 }
+
+func TestOperationsFromCSVPrefixes(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	var ops Operations
+	for i := 0; i < 40; i++ {
+		ops = append(ops, Operation{
+			OpType:   "PUT",
+			Thread:   uint16(i % 4),
+			Size:     1024,
+			ObjPerOp: 1,
+			File:     []string{"aaa", "bbb", "ccc", "ddd"}[i%4] + "/obj-" + time.Duration(i).String() + ".rnd",
+			Start:    start.Add(time.Duration(i) * time.Millisecond),
+			End:      start.Add(time.Duration(i+1) * time.Millisecond),
+		})
+	}
+	ops = append(ops, Operation{OpType: "LIST", File: "eee", Start: start, End: start.Add(time.Millisecond)})
+	var buf bytes.Buffer
+	if err := ops.CSV(&buf, ""); err != nil {
+		t.Fatal(err)
+	}
+	got, err := OperationsFromCSV(&buf, true, 0, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byPrefix := got.ByPrefix()
+	if len(byPrefix) != 5 {
+		t.Fatalf("got %d prefixes, want 5", len(byPrefix))
+	}
+	for _, p := range []string{"aaa", "bbb", "ccc", "ddd"} {
+		if n := len(byPrefix[p]); n != 10 {
+			t.Errorf("prefix %s: got %d operations, want 10", p, n)
+		}
+	}
+	if n := len(byPrefix["eee"]); n != 1 {
+		t.Errorf("list prefix: got %d operations, want 1", n)
+	}
+	// Names are still mapped to numbers, so distinct objects remain distinct.
+	names := make(map[string]struct{})
+	for _, op := range got {
+		names[op.File] = struct{}{}
+	}
+	if len(names) != len(ops) {
+		t.Errorf("got %d names, want %d", len(names), len(ops))
+	}
+}
@@ -19,17 +19,20 @@ package bench
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
 	"fmt"
+	"io"
 	"math"
-	"os"
+	"math/rand"
+	"net/http"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio/pkg/console"
+	"github.com/minio/warp/pkg/accesslog"
 	"github.com/minio/warp/pkg/generator"
+	"github.com/minio/warp/pkg/model"
 )
 
 type Benchmark interface {
@@ -56,6 +59,10 @@ type Common struct {
 	Bucket      string
 	Location    string
 
+	// BucketSet spreads objects across several buckets when set.
+	// Bucket is not used for objects in that case.
+	BucketSet *BucketSet
+
 	// Running in client mode.
 	ClientMode bool
 	// Clear bucket before benchmark
@@ -71,8 +78,24 @@ type Common struct {
 	// Default Put options.
 	PutOpts minio.PutObjectOptions
 
-	// add by guo.hao
-	AccessLog *os.File
+	// AccessLog receives a record for every request when set.
+	AccessLog *accesslog.Writer
+
+	// SLOs are the latency objectives operations are checked against.
+	SLOs SLOs
+
+	// Verify checks the content of downloads when set.
+	Verify *Verifier
+
+	// Rate schedules operation starts when set.
+	// Latency is then measured from the scheduled start.
+	Rate *RateLimiter
+
+	// Ramp spreads the start of threads when set.
+	Ramp *Ramp
+
+	// KeyDist selects the objects read. Objects are selected uniformly if nil.
+	KeyDist *KeyDist
 
 	// Error should log an error similar to fmt.Print(data...)
 	Error func(data ...interface{})
@@ -95,19 +118,55 @@ func (c *Common) ErrorF(format string, data ...interface{}) {
 	c.Error(fmt.Sprintf(format, data...))
 }
 
-// createEmptyBucket will create an empty bucket
-// or delete all content if it already exists.
+// buckets returns all buckets objects are stored in.
+func (c *Common) buckets() []string {
+	if c.BucketSet == nil {
+		return []string{c.Bucket}
+	}
+	return c.BucketSet.Names
+}
+
+// bucketFor returns the bucket an object is stored in.
+func (c *Common) bucketFor(object string) string {
+	if c.BucketSet == nil {
+		return c.Bucket
+	}
+	return c.BucketSet.For(object)
+}
+
+// createEmptyBucket will create empty buckets
+// or delete all content if they already exist.
 func (c *Common) createEmptyBucket(ctx context.Context) error {
+	c.Versioned = false
+	for _, bucket := range c.buckets() {
+		if err := c.createBucket(ctx, bucket); err != nil {
+			return err
+		}
+	}
+	if c.Clear {
+		if buckets := c.buckets(); len(buckets) > 1 {
+			console.Infof("\rClearing %d Buckets...", len(buckets))
+		} else {
+			console.Infof("\rClearing Bucket %q...", c.Bucket)
+		}
+		c.deleteAllInBucket(ctx)
+	}
+	return nil
+}
+
+// createBucket will create a bucket if it doesn't exist.
+// Versioned is set if the bucket has versioning enabled.
+func (c *Common) createBucket(ctx context.Context, bucket string) error {
 	cl, done := c.Client()
 	defer done()
-	x, err := cl.BucketExists(ctx, c.Bucket)
+	x, err := cl.BucketExists(ctx, bucket)
 	if err != nil {
 		return err
 	}
 
 	if !x {
-		console.Infof("\rCreating Bucket %q...", c.Bucket)
-		err := cl.MakeBucket(ctx, c.Bucket, minio.MakeBucketOptions{
+		console.Infof("\rCreating Bucket %q...", bucket)
+		err := cl.MakeBucket(ctx, bucket, minio.MakeBucketOptions{
 			Region: c.Location,
 		})
 
@@ -115,7 +174,7 @@ func (c *Common) createEmptyBucket(ctx context.Context) error {
 		// Check if it exists now.
 		// We don't test against a specific error since we might run against many different servers.
 		if err != nil {
-			x, err2 := cl.BucketExists(ctx, c.Bucket)
+			x, err2 := cl.BucketExists(ctx, bucket)
 			if err2 != nil {
 				return err2
 			}
@@ -125,106 +184,265 @@ func (c *Common) createEmptyBucket(ctx context.Context) error {
 			}
 		}
 	}
-	if bvc, err := cl.GetBucketVersioning(ctx, c.Bucket); err == nil {
-		c.Versioned = bvc.Status == "Enabled"
+	if bvc, err := cl.GetBucketVersioning(ctx, bucket); err == nil && bvc.Status == "Enabled" {
+		c.Versioned = true
 	}
+	return nil
+}
 
-	if c.Clear {
-		console.Infof("\rClearing Bucket %q...", c.Bucket)
-		c.deleteAllInBucket(ctx)
+// logOp writes the operation to the access log, if enabled.
+// versionID is the object version the operation was performed on, if any.
+func (c *Common) logOp(op Operation, versionID string) {
+	if c.AccessLog == nil {
+		return
 	}
-	return nil
+	c.writeAccessLog(c.opRecord(op, versionID))
 }
 
-// add by guo.hao
-func (c *Common) createAccessLog(ctx context.Context, logpath string) error {
-	new_file, err := os.Create(logpath)
-	if err != nil {
-		return err
+// opRecord returns the access log record of an operation.
+func (c *Common) opRecord(op Operation, versionID string) accesslog.Record {
+	rec := accesslog.Record{
+		Status:    accesslog.StatusSuccess,
+		Action:    strings.ToLower(op.OpType),
+		Bucket:    op.Bucket,
+		Object:    op.File,
+		VersionID: versionID,
+		Size:      op.Size,
+		Objects:   op.ObjPerOp,
+		Endpoint:  op.Endpoint,
+		Thread:    op.Thread,
+		Start:     op.Start,
+		Cost:      op.Duration().Seconds() * 1000,
+		Slow:      c.SLOs.Verdict(op) == SLOViolated,
+		Msg:       op.Err,
 	}
-	c.AccessLog = new_file
-	return nil
+	if rec.Bucket == "" {
+		rec.Bucket = c.bucketFor(op.File)
+	}
+	if op.Err != "" {
+		rec.Status = accesslog.StatusError
+	}
+	return rec
 }
 
-func (c *Common) writeAccessLog(content map[string]interface{}) error {
+// writeAccessLog queues a record to the access log, if enabled.
+func (c *Common) writeAccessLog(r accesslog.Record) {
 	if c.AccessLog == nil {
-		return errors.New("access log not init...")
+		return
 	}
-	bytes, e := json.Marshal(content)
-	if e != nil {
-		return e
+	if err := c.AccessLog.Write(r); err != nil {
+		c.Error("access log:", err)
 	}
-	_, err := c.AccessLog.Write(bytes)
-	c.AccessLog.Write([]byte("\n"))
-	return err
 }
 
-func (c *Common) closeAccessLog() error {
+// CloseAccessLog flushes and closes the access log, if enabled.
+// It is safe to call this multiple times.
+func (c *Common) CloseAccessLog() error {
 	if c.AccessLog == nil {
-		return errors.New("access log not init...")
+		return nil
 	}
 	return c.AccessLog.Close()
 }
 
-// deleteAllInBucket will delete all content in a bucket.
-// If no prefixes are specified everything in bucket is deleted.
+// uploadedPrefixes keeps the prefixes of uploaded objects,
+// so cleanup only deletes what was uploaded.
+// It is safe for concurrent use.
+type uploadedPrefixes struct {
+	mu       sync.Mutex
+	prefixes map[string]struct{}
+}
+
+// add records the prefix of an uploaded object.
+func (u *uploadedPrefixes) add(prefix string) {
+	u.mu.Lock()
+	if u.prefixes == nil {
+		u.prefixes = make(map[string]struct{})
+	}
+	u.prefixes[prefix] = struct{}{}
+	u.mu.Unlock()
+}
+
+// deleteAll deletes all objects in the recorded prefixes.
+// Nothing is deleted if no objects were uploaded.
+func (u *uploadedPrefixes) deleteAll(ctx context.Context, c *Common) {
+	u.mu.Lock()
+	prefixes := make([]string, 0, len(u.prefixes))
+	for p := range u.prefixes {
+		prefixes = append(prefixes, p)
+	}
+	u.mu.Unlock()
+	if len(prefixes) == 0 {
+		return
+	}
+	c.deleteAllInBucket(ctx, prefixes...)
+}
+
+// deleteAllInBucket will delete all content in the buckets.
+// If no prefixes are specified everything in the buckets is deleted.
 func (c *Common) deleteAllInBucket(ctx context.Context, prefixes ...string) {
 	if len(prefixes) == 0 {
 		prefixes = []string{""}
 	}
 	var wg sync.WaitGroup
-	wg.Add(len(prefixes))
-	for _, prefix := range prefixes {
-		go func(prefix string) {
-			defer wg.Done()
+	for _, bucket := range c.buckets() {
+		wg.Add(len(prefixes))
+		for _, prefix := range prefixes {
+			go c.deletePrefix(ctx, &wg, bucket, prefix)
+		}
+	}
+	wg.Wait()
+}
 
-			doneCh := make(chan struct{})
-			defer close(doneCh)
-			cl, done := c.Client()
-			defer done()
-			remove := make(chan minio.ObjectInfo, 1000)
-			errCh := cl.RemoveObjects(ctx, c.Bucket, remove, minio.RemoveObjectsOptions{})
-			defer func() {
-				// Signal we are done
-				close(remove)
-				// Wait for deletes to finish
-				err := <-errCh
-				if err.Err != nil {
-					c.Error(err.Err)
-				}
-			}()
+// deletePrefix will delete everything in a bucket with the specified prefix.
+func (c *Common) deletePrefix(ctx context.Context, wg *sync.WaitGroup, bucket, prefix string) {
+	defer wg.Done()
 
-			objects := cl.ListObjects(ctx, c.Bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true, WithVersions: c.Versioned})
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	cl, done := c.Client()
+	defer done()
+	remove := make(chan minio.ObjectInfo, 1000)
+	errCh := cl.RemoveObjects(ctx, bucket, remove, minio.RemoveObjectsOptions{})
+	defer func() {
+		// Signal we are done
+		close(remove)
+		// Wait for deletes to finish
+		err := <-errCh
+		if err.Err != nil {
+			c.Error(err.Err)
+		}
+	}()
+
+	objects := cl.ListObjects(ctx, bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true, WithVersions: c.Versioned})
+	for {
+		select {
+		case obj, ok := <-objects:
+			if !ok {
+				return
+			}
+			if obj.Err != nil {
+				c.Error(obj.Err)
+				continue
+			}
+		sendNext:
 			for {
 				select {
-				case obj, ok := <-objects:
-					if !ok {
-						return
-					}
-					if obj.Err != nil {
-						c.Error(obj.Err)
-						continue
-					}
-				sendNext:
-					for {
-						select {
-						case remove <- minio.ObjectInfo{
-							Key:       obj.Key,
-							VersionID: obj.VersionID,
-						}:
-							break sendNext
-						case err := <-errCh:
-							c.Error(err)
-						}
-					}
+				case remove <- minio.ObjectInfo{
+					Key:       obj.Key,
+					VersionID: obj.VersionID,
+				}:
+					break sendNext
 				case err := <-errCh:
 					c.Error(err)
 				}
 			}
-		}(prefix)
+		case err := <-errCh:
+			c.Error(err)
+		}
 	}
-	wg.Wait()
+}
 
+// uploadObjects uploads n objects from the source using all threads, while preparing a benchmark.
+// If sizes is non-nil, the size of each object is sampled from it instead of using the generated size.
+// Uploads are added to the verifier, if any, and sent to the collector, if any.
+// The objects uploaded are returned, also if an upload failed.
+func (c *Common) uploadObjects(ctx context.Context, n int, col *Collector, sizes model.Histogram) (generator.Objects, error) {
+	src := c.Source()
+	console.Info("\rUploading ", n, " objects of ", src.String())
+	var wg sync.WaitGroup
+	wg.Add(c.Concurrency)
+	obj := make(chan struct{}, n)
+	for i := 0; i < n; i++ {
+		obj <- struct{}{}
+	}
+	close(obj)
+	var groupErr error
+	var mu sync.Mutex
+	objs := make(generator.Objects, 0, n)
+
+	for i := 0; i < c.Concurrency; i++ {
+		go func(i int) {
+			defer wg.Done()
+			src := c.Source()
+			rng := rand.New(rand.NewSource(int64(i)))
+			var rcv chan<- Operation
+			if col != nil {
+				rcv = col.Receiver()
+			}
+			upload := func() error {
+				opts := c.PutOpts
+				obj := src.Object()
+				var r io.Reader = obj.Reader
+				if sizes != nil {
+					obj.Size = sizes.Sample(rng)
+					r = io.LimitReader(obj.Reader, obj.Size)
+				}
+				client, cldone := c.Client()
+				defer cldone()
+				op := Operation{
+					OpType:   http.MethodPut,
+					Thread:   uint16(i),
+					Size:     obj.Size,
+					File:     obj.Name,
+					ObjPerOp: 1,
+					Endpoint: client.EndpointURL().String(),
+				}
+				opts.ContentType = obj.ContentType
+				sums, err := c.verifySums(obj)
+				if err != nil {
+					return err
+				}
+				op.Start = time.Now()
+				res, err := client.PutObject(ctx, c.bucketFor(obj.Name), obj.Name, r, obj.Size, opts)
+				op.End = time.Now()
+				if err != nil {
+					err := fmt.Errorf("upload error: %w", err)
+					op.Err = err.Error()
+					c.logOp(op, "")
+					return err
+				}
+				obj.VersionID = res.VersionID
+				if res.Size != obj.Size {
+					err := fmt.Errorf("short upload. want: %d, got %d", obj.Size, res.Size)
+					op.Err = err.Error()
+					c.logOp(op, obj.VersionID)
+					return err
+				}
+				c.verifyAdd(*obj, sums)
+				rec := c.opRecord(op, obj.VersionID)
+				rec.ETag = res.ETag
+				c.writeAccessLog(rec)
+
+				mu.Lock()
+				obj.Reader = nil
+				objs = append(objs, *obj)
+				c.prepareProgress(float64(len(objs)) / float64(n))
+				mu.Unlock()
+				if rcv != nil {
+					rcv <- op
+				}
+				return nil
+			}
+			for range obj {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+				}
+				if err := upload(); err != nil {
+					c.Error(err)
+					mu.Lock()
+					if groupErr == nil {
+						groupErr = err
+					}
+					mu.Unlock()
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	return objs, groupErr
 }
 
 // prepareProgress updates preparation progess with the value 0->1.
@@ -0,0 +1,209 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/tags"
+	"github.com/minio/minio/pkg/console"
+)
+
+// Buckets benchmarks creating, configuring and removing buckets.
+type Buckets struct {
+	// Buckets is the number of buckets kept before the oldest is removed.
+	Buckets int
+	// Config will set versioning, tagging and a policy on every created bucket.
+	Config bool
+	Common
+
+	// prefix of all created bucket names.
+	prefix string
+
+	// Buckets that have been created and not removed.
+	mu      sync.Mutex
+	created map[string]struct{}
+}
+
+// bucketPolicy is set on created buckets when Config is enabled.
+const bucketPolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},"Action":["s3:GetObject"],"Resource":["arn:aws:s3:::%s/*"]}]}`
+
+// Prepare will choose a unique prefix for created bucket names.
+func (b *Buckets) Prepare(ctx context.Context) error {
+	if b.Buckets < b.Concurrency {
+		return errors.New("number of buckets must be at least the concurrency")
+	}
+	var id [3]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return err
+	}
+	b.prefix = fmt.Sprintf("%s-%s", b.Bucket, hex.EncodeToString(id[:]))
+	// Leave room for thread and sequence numbers.
+	if len(b.prefix) > 45 {
+		return fmt.Errorf("bucket name %q is too long to be used as prefix", b.Bucket)
+	}
+	b.created = make(map[string]struct{}, b.Buckets)
+	console.Info("\rCreating buckets named ", b.prefix, "-*")
+	return nil
+}
+
+// Start will execute the main benchmark.
+// Each thread creates buckets until it has its share of Buckets
+// and then removes the oldest bucket before creating a new one.
+// Operations should begin executing when the start channel is closed.
+func (b *Buckets) Start(ctx context.Context, wait chan struct{}) (Operations, error) {
+	var wg sync.WaitGroup
+	wg.Add(b.Concurrency)
+	c := NewCollector()
+	if b.AutoTermDur > 0 {
+		ctx = c.AutoTerm(ctx, "MAKE_BUCKET", b.AutoTermScale, autoTermCheck, autoTermSamples, b.AutoTermDur)
+	}
+	perThread := b.Buckets / b.Concurrency
+
+	// Non-terminating context.
+	nonTerm := context.Background()
+
+	for i := 0; i < b.Concurrency; i++ {
+		go func(i int) {
+			rcv := c.Receiver()
+			defer wg.Done()
+			done := ctx.Done()
+			var live []string
+
+			<-wait
+			for n := 0; ; n++ {
+				intended, ok := b.nextStart(i, done)
+				if !ok {
+					return
+				}
+				client, cldone := b.Client()
+				do := func(opType, bucket string, fn func() error) error {
+					op := Operation{
+						OpType:   opType,
+						Thread:   uint16(i),
+						Bucket:   bucket,
+						ObjPerOp: 1,
+						Endpoint: client.EndpointURL().String(),
+						// Only the first operation of each cycle is scheduled.
+						Intended: intended,
+					}
+					intended = time.Time{}
+					op.Start = time.Now()
+					err := fn()
+					op.End = time.Now()
+					if err != nil {
+						b.Error(opType, " error: ", err)
+						op.Err = err.Error()
+					}
+					b.logOp(op, "")
+					rcv <- op
+					return err
+				}
+
+				if len(live) >= perThread {
+					bucket := live[0]
+					live = live[1:]
+					if do("REMOVE_BUCKET", bucket, func() error {
+						return client.RemoveBucket(nonTerm, bucket)
+					}) == nil {
+						b.mu.Lock()
+						delete(b.created, bucket)
+						b.mu.Unlock()
+					}
+				}
+
+				bucket := fmt.Sprintf("%s-%d-%d", b.prefix, i, n)
+				err := do("MAKE_BUCKET", bucket, func() error {
+					return client.MakeBucket(nonTerm, bucket, minio.MakeBucketOptions{Region: b.Location})
+				})
+				if err != nil {
+					cldone()
+					continue
+				}
+				b.mu.Lock()
+				b.created[bucket] = struct{}{}
+				b.mu.Unlock()
+				live = append(live, bucket)
+
+				do("BUCKET_EXISTS", bucket, func() error {
+					ok, err := client.BucketExists(nonTerm, bucket)
+					if err == nil && !ok {
+						err = errors.New("bucket does not exist after creation")
+					}
+					return err
+				})
+				if b.Config {
+					do("PUT_BUCKET_VERSIONING", bucket, func() error {
+						return client.EnableVersioning(nonTerm, bucket)
+					})
+					do("PUT_BUCKET_TAGGING", bucket, func() error {
+						t, err := tags.MapToBucketTags(map[string]string{"warp-bucket": bucket})
+						if err != nil {
+							return err
+						}
+						return client.SetBucketTagging(nonTerm, bucket, t)
+					})
+					do("PUT_BUCKET_POLICY", bucket, func() error {
+						return client.SetBucketPolicy(nonTerm, bucket, fmt.Sprintf(bucketPolicy, bucket))
+					})
+				}
+				do("LIST_BUCKETS", "", func() error {
+					_, err := client.ListBuckets(nonTerm)
+					return err
+				})
+				cldone()
+			}
+		}(i)
+	}
+	wg.Wait()
+	return c.Close(), nil
+}
+
+// Cleanup removes all buckets created by the benchmark.
+func (b *Buckets) Cleanup(ctx context.Context) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	buckets := make(chan string, len(b.created))
+	for bucket := range b.created {
+		buckets <- bucket
+	}
+	close(buckets)
+	var wg sync.WaitGroup
+	wg.Add(b.Concurrency)
+	for i := 0; i < b.Concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			client, cldone := b.Client()
+			defer cldone()
+			for bucket := range buckets {
+				if err := client.RemoveBucket(ctx, bucket); err != nil {
+					b.Error("remove bucket error: ", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	b.created = nil
+}
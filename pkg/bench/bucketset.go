@@ -0,0 +1,56 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"errors"
+	"hash/fnv"
+
+	"github.com/minio/warp/pkg/model"
+)
+
+// BucketSet spreads objects across several buckets.
+// Objects are assigned to buckets by hashing their names,
+// so the bucket of an object never has to be stored.
+type BucketSet struct {
+	Names []string
+	// zipf assigns the share of objects for each bucket.
+	zipf *model.Zipf
+}
+
+// NewBucketSet returns a set of buckets.
+// With skew 0 objects are spread evenly across the buckets,
+// otherwise bucket n receives a share of the objects proportional to 1/(n+1)^skew.
+func NewBucketSet(names []string, skew float64) (*BucketSet, error) {
+	if len(names) == 0 {
+		return nil, errors.New("no buckets specified")
+	}
+	if skew < 0 {
+		return nil, errors.New("bucket skew cannot be negative")
+	}
+	return &BucketSet{Names: names, zipf: model.NewZipf(len(names), skew)}, nil
+}
+
+// For returns the bucket of an object.
+func (b *BucketSet) For(object string) string {
+	h := fnv.New64a()
+	h.Write([]byte(object))
+	// Use the top 53 bits as a uniform value in [0, 1).
+	u := float64(h.Sum64()>>11) / (1 << 53)
+	return b.Names[b.zipf.RankAt(u)]
+}
@@ -0,0 +1,98 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/minio/warp/pkg/generator"
+)
+
+func TestBucketSet(t *testing.T) {
+	names := []string{"b0", "b1", "b2", "b3"}
+	tests := []struct {
+		skew float64
+		want []float64
+	}{
+		{skew: 0, want: []float64{0.25, 0.25, 0.25, 0.25}},
+		// Shares are proportional to 1, 1/2, 1/3, 1/4.
+		{skew: 1, want: []float64{0.48, 0.24, 0.16, 0.12}},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprint("skew-", test.skew), func(t *testing.T) {
+			set, err := NewBucketSet(names, test.skew)
+			if err != nil {
+				t.Fatal(err)
+			}
+			const n = 100000
+			got := make(map[string]int)
+			for i := 0; i < n; i++ {
+				got[set.For(fmt.Sprintf("prefix/object-%d.rnd", i))]++
+			}
+			for i, name := range names {
+				share := float64(got[name]) / n
+				if math.Abs(share-test.want[i]) > 0.01 {
+					t.Errorf("bucket %s: got share %.3f, want %.3f", name, share, test.want[i])
+				}
+			}
+			if a, b := set.For("same"), set.For("same"); a != b {
+				t.Errorf("object assigned to %s and %s", a, b)
+			}
+		})
+	}
+	if _, err := NewBucketSet(nil, 0); err == nil {
+		t.Error("want error for empty set")
+	}
+}
+
+func TestDeleteBatches(t *testing.T) {
+	set, err := NewBucketSet([]string{"b0", "b1", "b2"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := Delete{BatchSize: 10, Common: Common{BucketSet: set}}
+	for i := 0; i < 1000; i++ {
+		d.objects = append(d.objects, generator.Object{Name: fmt.Sprintf("prefix/object-%d.rnd", i)})
+	}
+	d.groupByBucket()
+	seen := make(map[string]struct{})
+	for {
+		objs := d.takeBatch()
+		if len(objs) == 0 {
+			break
+		}
+		if len(objs) > d.BatchSize {
+			t.Fatalf("batch of %d objects", len(objs))
+		}
+		bucket := set.For(objs[0].Name)
+		for _, obj := range objs {
+			if b := set.For(obj.Name); b != bucket {
+				t.Fatalf("batch contains objects from %s and %s", bucket, b)
+			}
+			seen[obj.Name] = struct{}{}
+		}
+	}
+	if len(seen) != len(d.objects) {
+		t.Errorf("deleted %d objects, want %d", len(seen), len(d.objects))
+	}
+	if n := len(d.remaining()); n != 0 {
+		t.Errorf("%d objects remaining", n)
+	}
+}
@@ -0,0 +1,308 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio/pkg/console"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// OpVisibility is the operation type recorded when a check did not see the latest write.
+// It lasts from the end of the write until the write was observed.
+const OpVisibility = "VISIBILITY"
+
+// consistencyPoll is the time between checks while waiting for a write to become visible.
+const consistencyPoll = 10 * time.Millisecond
+
+// Consistency checks that reads and listings observe the latest write.
+// Each thread writes, overwrites and deletes its own objects and checks
+// each object with GET, HEAD and LIST after every write.
+type Consistency struct {
+	// Objects is the number of object names used.
+	Objects int
+	// DeleteRatio is the fraction of writes that delete the object instead of overwriting it.
+	DeleteRatio float64
+	// VisibilityTimeout is the maximum time to wait for a write to become visible.
+	VisibilityTimeout time.Duration
+	// ReadClient is used for checks when set.
+	// Otherwise Client is used.
+	ReadClient func() (cl *minio.Client, done func())
+
+	Collector *Collector
+	GetOpts   minio.GetObjectOptions
+	StatOpts  minio.StatObjectOptions
+	Common
+
+	keys generator.Objects
+}
+
+// consistencyKey is the latest write of an object.
+type consistencyKey struct {
+	name   string
+	exists bool
+	etag   string
+	size   int64
+}
+
+// Prepare will create an empty bucket or delete any content already there
+// and choose the object names.
+func (c *Consistency) Prepare(ctx context.Context) error {
+	if c.Objects < c.Concurrency {
+		return errors.New("number of objects must be at least the concurrency")
+	}
+	c.Collector = NewCollector()
+	src := c.Source()
+	c.keys = make(generator.Objects, c.Objects)
+	for i := range c.keys {
+		c.keys[i] = generator.Object{Name: src.Object().Name}
+	}
+	console.Info("\rChecking ", c.Objects, " objects of ", src.String())
+	return c.createEmptyBucket(ctx)
+}
+
+// Start will execute the main benchmark.
+// Operations should begin executing when the start channel is closed.
+func (c *Consistency) Start(ctx context.Context, wait chan struct{}) (Operations, error) {
+	var wg sync.WaitGroup
+	wg.Add(c.Concurrency)
+	col := c.Collector.reopen()
+	if c.AutoTermDur > 0 {
+		ctx = col.AutoTerm(ctx, "", c.AutoTermScale, autoTermCheck, autoTermSamples, c.AutoTermDur)
+	}
+	readClient := c.ReadClient
+	if readClient == nil {
+		readClient = c.Client
+	}
+
+	// Non-terminating context.
+	nonTerm := context.Background()
+
+	for i := 0; i < c.Concurrency; i++ {
+		go func(i int) {
+			rng := rand.New(rand.NewSource(int64(i)))
+			rcv := col.Receiver()
+			defer wg.Done()
+			done := ctx.Done()
+			src := c.Source()
+			var keys []*consistencyKey
+			for j := i; j < len(c.keys); j += c.Concurrency {
+				keys = append(keys, &consistencyKey{name: c.keys[j].Name})
+			}
+
+			<-wait
+			for {
+				intended, ok := c.nextStart(i, done)
+				if !ok {
+					return
+				}
+				k := keys[rng.Intn(len(keys))]
+				client, clDone := c.Client()
+				op := c.write(nonTerm, client, k, src, rng)
+				clDone()
+				op.Thread = uint16(i)
+				op.Intended = intended
+				rcv <- op
+				if op.Err != "" {
+					continue
+				}
+				written := op.End
+				for _, check := range []string{http.MethodGet, "STAT", "LIST"} {
+					client, clDone := readClient()
+					op := c.check(nonTerm, client, check, k)
+					op.Thread = uint16(i)
+					rcv <- op
+					if op.ErrKind != ErrKindNone {
+						if vis, ok := c.waitVisible(nonTerm, client, check, k, written, done); ok {
+							vis.Thread = uint16(i)
+							rcv <- vis
+						}
+					}
+					clDone()
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	return col.Close(), nil
+}
+
+// write uploads a new version of k or deletes it.
+func (c *Consistency) write(ctx context.Context, client *minio.Client, k *consistencyKey, src generator.Source, rng *rand.Rand) Operation {
+	op := Operation{
+		OpType:   http.MethodPut,
+		File:     k.name,
+		ObjPerOp: 1,
+		Endpoint: client.EndpointURL().String(),
+	}
+	if k.exists && rng.Float64() < c.DeleteRatio {
+		op.OpType = http.MethodDelete
+		op.Start = time.Now()
+		err := client.RemoveObject(ctx, c.bucketFor(k.name), k.name, minio.RemoveObjectOptions{})
+		op.End = time.Now()
+		if err != nil {
+			c.Error("delete error: ", err)
+			op.Err = err.Error()
+		} else {
+			*k = consistencyKey{name: k.name}
+		}
+		c.logOp(op, "")
+		return op
+	}
+
+	obj := src.Object()
+	op.Size = obj.Size
+	opts := c.PutOpts
+	opts.ContentType = obj.ContentType
+	op.Start = time.Now()
+	res, err := client.PutObject(ctx, c.bucketFor(k.name), k.name, obj.Reader, obj.Size, opts)
+	op.End = time.Now()
+	if err == nil && res.Size != obj.Size {
+		err = fmt.Errorf("short upload. want: %d, got %d", obj.Size, res.Size)
+	}
+	if err != nil {
+		c.Error("upload error: ", err)
+		op.Err = err.Error()
+		// The object may or may not have been overwritten.
+		// It is not checked until the next successful write.
+		*k = consistencyKey{name: k.name, exists: true}
+	} else {
+		*k = consistencyKey{name: k.name, exists: true, etag: res.ETag, size: obj.Size}
+	}
+	c.logOp(op, res.VersionID)
+	return op
+}
+
+// check reads k with the specified operation and compares the result to the latest write.
+// If the result is not the latest write the ErrKind of the operation is set.
+func (c *Consistency) check(ctx context.Context, client *minio.Client, opType string, k *consistencyKey) Operation {
+	op := Operation{
+		OpType:   opType,
+		File:     k.name,
+		ObjPerOp: 1,
+		Endpoint: client.EndpointURL().String(),
+	}
+	var found bool
+	var etag string
+	var size int64
+	var err error
+	bucket := c.bucketFor(k.name)
+	op.Start = time.Now()
+	switch opType {
+	case http.MethodGet:
+		var o *minio.Object
+		o, err = client.GetObject(ctx, bucket, k.name, c.GetOpts)
+		if err != nil {
+			break
+		}
+		var info minio.ObjectInfo
+		info, err = o.Stat()
+		if err == nil {
+			fbr := firstByteRecorder{r: o}
+			op.Size, err = io.Copy(ioutil.Discard, &fbr)
+			op.FirstByte = fbr.t
+			found, etag, size = true, info.ETag, info.Size
+		}
+		o.Close()
+	case "STAT":
+		var info minio.ObjectInfo
+		info, err = client.StatObject(ctx, bucket, k.name, c.StatOpts)
+		if err == nil {
+			found, etag, size = true, info.ETag, info.Size
+		}
+	case "LIST":
+		lctx, cancel := context.WithCancel(ctx)
+		for obj := range client.ListObjects(lctx, bucket, minio.ListObjectsOptions{Prefix: k.name}) {
+			if obj.Err != nil {
+				err = obj.Err
+				break
+			}
+			if obj.Key == k.name {
+				found, etag, size = true, obj.ETag, obj.Size
+				break
+			}
+		}
+		cancel()
+	}
+	op.End = time.Now()
+	if err != nil && minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		err = nil
+	}
+	switch {
+	case err != nil:
+		c.Error(opType, " error: ", err)
+		op.Err = err.Error()
+	case k.exists && !found:
+		op.Err = "missing: latest write not found"
+		op.ErrKind = ErrKindMissing
+	case !k.exists && found:
+		op.Err = "stale: deleted object found"
+		op.ErrKind = ErrKindStale
+	case found && (etag != k.etag || size != k.size):
+		op.Err = fmt.Sprintf("stale: got etag %s, size %d. want etag %s, size %d", etag, size, k.etag, k.size)
+		op.ErrKind = ErrKindStale
+	}
+	c.logOp(op, "")
+	return op
+}
+
+// waitVisible repeats a check until it observes the latest write.
+// The returned operation lasts from the end of the write until the check succeeded.
+// false is returned if done is closed before the write was visible.
+func (c *Consistency) waitVisible(ctx context.Context, client *minio.Client, opType string, k *consistencyKey, written time.Time, done <-chan struct{}) (Operation, bool) {
+	vis := Operation{
+		OpType:   OpVisibility,
+		File:     k.name,
+		ObjPerOp: 1,
+		Endpoint: client.EndpointURL().String(),
+		Start:    written,
+	}
+	timeout := time.NewTimer(c.VisibilityTimeout)
+	defer timeout.Stop()
+	for {
+		select {
+		case <-timeout.C:
+			vis.End = time.Now()
+			vis.Err = fmt.Sprintf("%s: latest write not visible after %v", opType, c.VisibilityTimeout)
+			return vis, true
+		case <-done:
+			return vis, false
+		case <-time.After(consistencyPoll):
+		}
+		op := c.check(ctx, client, opType, k)
+		if op.Err == "" {
+			vis.End = op.End
+			return vis, true
+		}
+	}
+}
+
+// Cleanup deletes everything uploaded to the bucket.
+func (c *Consistency) Cleanup(ctx context.Context) {
+	c.deleteAllInBucket(ctx, c.keys.Prefixes()...)
+}
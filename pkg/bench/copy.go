@@ -0,0 +1,275 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/minio/minio/pkg/console"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// Copy benchmarks server side copy speed.
+type Copy struct {
+	CreateObjects int
+	Collector     *Collector
+	objects       generator.Objects
+
+	// DestBucket is the bucket objects are copied to.
+	// If empty objects are copied within the source bucket.
+	DestBucket string
+	// PartSize enables multipart copy of objects bigger than this using parts of this size.
+	// Objects are copied with a single request if 0.
+	PartSize int64
+
+	// dst is used for creating and cleaning the destination bucket.
+	dst *Common
+	Common
+}
+
+// copySuffix is added to the name of objects copied within their source bucket.
+const copySuffix = ".copy"
+
+// Prepare will create empty buckets or delete any content already there
+// and upload a number of objects.
+func (g *Copy) Prepare(ctx context.Context) error {
+	if err := g.createEmptyBucket(ctx); err != nil {
+		return err
+	}
+	if g.DestBucket != "" && g.DestBucket != g.Bucket {
+		dst := g.Common
+		dst.Bucket = g.DestBucket
+		dst.BucketSet = nil
+		if err := dst.createEmptyBucket(ctx); err != nil {
+			return err
+		}
+		g.dst = &dst
+	}
+	src := g.Source()
+	console.Info("\rUploading ", g.CreateObjects, " objects of ", src.String())
+	var wg sync.WaitGroup
+	wg.Add(g.Concurrency)
+	g.Collector = NewCollector()
+	obj := make(chan struct{}, g.CreateObjects)
+	for i := 0; i < g.CreateObjects; i++ {
+		obj <- struct{}{}
+	}
+	close(obj)
+	var groupErr error
+	var mu sync.Mutex
+	for i := 0; i < g.Concurrency; i++ {
+		go func(i int) {
+			defer wg.Done()
+			src := g.Source()
+			for range obj {
+				opts := g.PutOpts
+				rcv := g.Collector.Receiver()
+				done := ctx.Done()
+
+				select {
+				case <-done:
+					return
+				default:
+				}
+				obj := src.Object()
+				client, cldone := g.Client()
+				op := Operation{
+					OpType:   http.MethodPut,
+					Thread:   uint16(i),
+					Size:     obj.Size,
+					File:     obj.Name,
+					ObjPerOp: 1,
+					Endpoint: client.EndpointURL().String(),
+				}
+				opts.ContentType = obj.ContentType
+				op.Start = time.Now()
+				res, err := client.PutObject(ctx, g.bucketFor(obj.Name), obj.Name, obj.Reader, obj.Size, opts)
+				op.End = time.Now()
+				if err != nil {
+					err := fmt.Errorf("upload error: %w", err)
+					g.Error(err)
+					mu.Lock()
+					if groupErr == nil {
+						groupErr = err
+					}
+					mu.Unlock()
+					op.Err = err.Error()
+					g.logOp(op, "")
+					return
+				}
+
+				obj.VersionID = res.VersionID
+				if res.Size != obj.Size {
+					err := fmt.Errorf("short upload. want: %d, got %d", obj.Size, res.Size)
+					g.Error(err)
+					mu.Lock()
+					if groupErr == nil {
+						groupErr = err
+					}
+					mu.Unlock()
+					op.Err = err.Error()
+					g.logOp(op, obj.VersionID)
+					return
+				}
+				g.logOp(op, obj.VersionID)
+				cldone()
+				mu.Lock()
+				obj.Reader = nil
+				g.objects = append(g.objects, *obj)
+				g.prepareProgress(float64(len(g.objects)) / float64(g.CreateObjects))
+				mu.Unlock()
+				rcv <- op
+			}
+		}(i)
+	}
+	wg.Wait()
+	return groupErr
+}
+
+// Start will execute the main benchmark.
+// Operations should begin executing when the start channel is closed.
+func (g *Copy) Start(ctx context.Context, wait chan struct{}) (Operations, error) {
+	var wg sync.WaitGroup
+	wg.Add(g.Concurrency)
+	c := g.Collector.reopen()
+	if g.AutoTermDur > 0 {
+		ctx = c.AutoTerm(ctx, "COPY", g.AutoTermScale, autoTermCheck, autoTermSamples, g.AutoTermDur)
+	}
+	// Non-terminating context.
+	nonTerm := context.Background()
+	dstSuffix := copySuffix
+	if g.dst != nil {
+		dstSuffix = ""
+	}
+
+	for i := 0; i < g.Concurrency; i++ {
+		go func(i int) {
+			rng := rand.New(rand.NewSource(int64(i)))
+			rcv := c.Receiver()
+			defer wg.Done()
+			done := ctx.Done()
+
+			<-wait
+			for {
+				intended, ok := g.nextStart(i, done)
+				if !ok {
+					return
+				}
+				obj := g.objects[g.objectIndex(rng, len(g.objects))]
+				client, cldone := g.Client()
+				op := Operation{
+					OpType:   "COPY",
+					Thread:   uint16(i),
+					Size:     obj.Size,
+					File:     obj.Name,
+					ObjPerOp: 1,
+					Endpoint: client.EndpointURL().String(),
+					Intended: intended,
+				}
+				srcBucket := g.bucketFor(obj.Name)
+				dstBucket := srcBucket
+				if g.dst != nil {
+					dstBucket = g.dst.Bucket
+				}
+				dst := minio.CopyDestOptions{
+					Bucket:     dstBucket,
+					Object:     obj.Name + dstSuffix,
+					Encryption: g.PutOpts.ServerSideEncryption,
+				}
+				src := minio.CopySrcOptions{
+					Bucket:    srcBucket,
+					Object:    obj.Name,
+					VersionID: obj.VersionID,
+				}
+				if sse := g.PutOpts.ServerSideEncryption; sse != nil && sse.Type() == encrypt.SSEC {
+					src.Encryption = sse
+				}
+				var err error
+				op.Start = time.Now()
+				if g.PartSize > 0 && obj.Size > g.PartSize {
+					err = g.copyMultipart(nonTerm, minio.Core{Client: client}, src, dst, obj.Size)
+				} else {
+					_, err = client.CopyObject(nonTerm, dst, src)
+				}
+				op.End = time.Now()
+				if err != nil {
+					g.Error("copy error: ", err)
+					op.Err = err.Error()
+				}
+				g.logOp(op, obj.VersionID)
+				rcv <- op
+				cldone()
+			}
+		}(i)
+	}
+	wg.Wait()
+	return c.Close(), nil
+}
+
+// copyMultipart copies an object of the specified size using a multipart upload.
+// The upload is aborted if any part fails.
+// Parts are copied from the source version, with the source and destination encryption headers,
+// the same way minio.Client.ComposeObject copies parts.
+func (g *Copy) copyMultipart(ctx context.Context, core minio.Core, src minio.CopySrcOptions, dst minio.CopyDestOptions, size int64) error {
+	h := make(http.Header)
+	src.Marshal(h)
+	if dst.Encryption != nil && dst.Encryption.Type() == encrypt.SSEC {
+		dst.Encryption.Marshal(h)
+	}
+	// Headers are passed as metadata and replace the source set by CopyObjectPart.
+	headers := make(map[string]string, len(h))
+	for k := range h {
+		headers[k] = h.Get(k)
+	}
+	uploadID, err := core.NewMultipartUpload(ctx, dst.Bucket, dst.Object, g.PutOpts)
+	if err != nil {
+		return err
+	}
+	var parts []minio.CompletePart
+	for start := int64(0); start < size; start += g.PartSize {
+		length := g.PartSize
+		if start+length > size {
+			length = size - start
+		}
+		part, err := core.CopyObjectPart(ctx, src.Bucket, src.Object, dst.Bucket, dst.Object, uploadID, len(parts)+1, start, length, headers)
+		if err != nil {
+			if aerr := core.AbortMultipartUpload(ctx, dst.Bucket, dst.Object, uploadID); aerr != nil {
+				g.Error("abort multipart error: ", aerr)
+			}
+			return err
+		}
+		parts = append(parts, part)
+	}
+	_, err = core.CompleteMultipartUpload(ctx, dst.Bucket, dst.Object, uploadID, parts)
+	return err
+}
+
+// Cleanup deletes everything uploaded to the buckets.
+func (g *Copy) Cleanup(ctx context.Context) {
+	g.deleteAllInBucket(ctx, g.objects.Prefixes()...)
+	if g.dst != nil {
+		g.dst.deleteAllInBucket(ctx, g.objects.Prefixes()...)
+	}
+}
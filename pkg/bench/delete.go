@@ -36,6 +36,10 @@ type Delete struct {
 	BatchSize     int
 	Collector     *Collector
 	objects       generator.Objects
+	// byBucket contains the objects left to delete, grouped by bucket.
+	byBucket []generator.Objects
+	// next is the group the next batch is taken from.
+	next int
 
 	Common
 }
@@ -84,7 +88,7 @@ func (d *Delete) Prepare(ctx context.Context) error {
 				}
 				opts.ContentType = obj.ContentType
 				op.Start = time.Now()
-				res, err := client.PutObject(ctx, d.Bucket, obj.Name, obj.Reader, obj.Size, opts)
+				res, err := client.PutObject(ctx, d.bucketFor(obj.Name), obj.Name, obj.Reader, obj.Size, opts)
 				op.End = time.Now()
 				if err != nil {
 					err := fmt.Errorf("upload error: %w", err)
@@ -94,6 +98,8 @@ func (d *Delete) Prepare(ctx context.Context) error {
 						groupErr = err
 					}
 					mu.Unlock()
+					op.Err = err.Error()
+					d.logOp(op, "")
 					return
 				}
 				obj.VersionID = res.VersionID
@@ -106,8 +112,11 @@ func (d *Delete) Prepare(ctx context.Context) error {
 						groupErr = err
 					}
 					mu.Unlock()
+					op.Err = err.Error()
+					d.logOp(op, obj.VersionID)
 					return
 				}
+				d.logOp(op, obj.VersionID)
 				cldone()
 				mu.Lock()
 				obj.Reader = nil
@@ -126,15 +135,38 @@ func (d *Delete) Prepare(ctx context.Context) error {
 	rand.Shuffle(len(a), func(i, j int) {
 		a[i], a[j] = a[j], a[i]
 	})
+	d.groupByBucket()
 	return groupErr
 }
 
+// groupByBucket groups the objects to delete by bucket,
+// so each batch only deletes objects from a single bucket.
+func (d *Delete) groupByBucket() {
+	d.next = 0
+	if d.BucketSet == nil {
+		d.byBucket = []generator.Objects{d.objects}
+		return
+	}
+	idx := make(map[string]int)
+	d.byBucket = d.byBucket[:0]
+	for _, obj := range d.objects {
+		bucket := d.bucketFor(obj.Name)
+		i, ok := idx[bucket]
+		if !ok {
+			i = len(d.byBucket)
+			idx[bucket] = i
+			d.byBucket = append(d.byBucket, nil)
+		}
+		d.byBucket[i] = append(d.byBucket[i], obj)
+	}
+}
+
 // Start will execute the main benchmark.
 // Operations should begin executing when the start channel is closed.
 func (d *Delete) Start(ctx context.Context, wait chan struct{}) (Operations, error) {
 	var wg sync.WaitGroup
 	wg.Add(d.Concurrency)
-	c := d.Collector
+	c := d.Collector.reopen()
 	if d.AutoTermDur > 0 {
 		ctx = c.AutoTerm(ctx, http.MethodDelete, d.AutoTermScale, autoTermCheck, autoTermSamples, d.AutoTermDur)
 	}
@@ -150,24 +182,18 @@ func (d *Delete) Start(ctx context.Context, wait chan struct{}) (Operations, err
 
 			<-wait
 			for {
-				select {
-				case <-done:
+				intended, ok := d.nextStart(i, done)
+				if !ok {
 					return
-				default:
 				}
 
 				// Fetch d.BatchSize objects
 				mu.Lock()
-				if len(d.objects) == 0 {
-					mu.Unlock()
+				objs := d.takeBatch()
+				mu.Unlock()
+				if len(objs) == 0 {
 					return
 				}
-				objs := d.objects
-				if len(objs) > d.BatchSize {
-					objs = objs[:d.BatchSize]
-				}
-				d.objects = d.objects[len(objs):]
-				mu.Unlock()
 
 				// Queue all in batch.
 				objects := make(chan minio.ObjectInfo, len(objs))
@@ -182,12 +208,14 @@ func (d *Delete) Start(ctx context.Context, wait chan struct{}) (Operations, err
 					Thread:   uint16(i),
 					Size:     0,
 					File:     "",
+					Bucket:   d.bucketFor(objs[0].Name),
 					ObjPerOp: len(objs),
 					Endpoint: client.EndpointURL().String(),
+					Intended: intended,
 				}
 				op.Start = time.Now()
 				// RemoveObjectsWithContext will split any batches > 1000 into separate requests.
-				errCh := client.RemoveObjects(nonTerm, d.Bucket, objects, minio.RemoveObjectsOptions{})
+				errCh := client.RemoveObjects(nonTerm, op.Bucket, objects, minio.RemoveObjectsOptions{})
 
 				// Wait for errCh to close.
 				for {
@@ -202,6 +230,7 @@ func (d *Delete) Start(ctx context.Context, wait chan struct{}) (Operations, err
 				}
 				op.End = time.Now()
 				cldone()
+				d.logOp(op, "")
 				rcv <- op
 			}
 		}(i)
@@ -210,9 +239,39 @@ func (d *Delete) Start(ctx context.Context, wait chan struct{}) (Operations, err
 	return c.Close(), nil
 }
 
+// takeBatch removes up to BatchSize objects stored in the same bucket
+// from the objects to delete and returns them.
+// Batches are taken from each bucket in turn.
+// Nil is returned when there are no more objects to delete.
+func (d *Delete) takeBatch() generator.Objects {
+	for range d.byBucket {
+		i := d.next % len(d.byBucket)
+		d.next++
+		objs := d.byBucket[i]
+		if len(objs) == 0 {
+			continue
+		}
+		if len(objs) > d.BatchSize {
+			objs = objs[:d.BatchSize]
+		}
+		d.byBucket[i] = d.byBucket[i][len(objs):]
+		return objs
+	}
+	return nil
+}
+
+// remaining returns the objects that have not been deleted.
+func (d *Delete) remaining() generator.Objects {
+	var objs generator.Objects
+	for _, b := range d.byBucket {
+		objs = append(objs, b...)
+	}
+	return objs
+}
+
 // Cleanup deletes everything uploaded to the bucket.
 func (d *Delete) Cleanup(ctx context.Context) {
-	if len(d.objects) > 0 {
-		d.deleteAllInBucket(ctx, d.objects.Prefixes()...)
+	if objs := d.remaining(); len(objs) > 0 {
+		d.deleteAllInBucket(ctx, objs.Prefixes()...)
 	}
 }
@@ -0,0 +1,134 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+
+	"github.com/minio/warp/pkg/model"
+)
+
+// Key distributions.
+const (
+	// KeyDistUniform selects all objects with the same probability.
+	KeyDistUniform = "uniform"
+	// KeyDistZipf selects objects with a Zipf distribution,
+	// so the first objects are the most popular.
+	KeyDistZipf = "zipf"
+	// KeyDistHotset sends a share of the requests to a share of the objects.
+	KeyDistHotset = "hotset"
+	// KeyDistSequential scans all objects in order.
+	KeyDistSequential = "sequential"
+)
+
+// KeyDist selects which objects are accessed.
+type KeyDist struct {
+	kind    string
+	skew    float64
+	hotKeys float64
+	hotReqs float64
+
+	// next is the next object of a sequential scan.
+	next uint64
+
+	// zipf contains a *model.Zipf covering at least the number of objects.
+	// It is replaced with a bigger one when the number of objects grows.
+	zipf   atomic.Value
+	zipfMu sync.Mutex
+}
+
+// NewKeyDist returns a key distribution of the specified kind.
+// skew is the exponent used by zipf.
+// For hotset, hotKeys is the share of objects receiving hotReqs share of the requests.
+func NewKeyDist(kind string, skew, hotKeys, hotReqs float64) (*KeyDist, error) {
+	k := KeyDist{kind: kind, skew: skew, hotKeys: hotKeys, hotReqs: hotReqs}
+	switch kind {
+	case KeyDistUniform, KeyDistSequential:
+	case KeyDistZipf:
+		if skew < 0 {
+			return nil, fmt.Errorf("zipf skew cannot be negative: %v", skew)
+		}
+	case KeyDistHotset:
+		if hotKeys <= 0 || hotKeys > 1 {
+			return nil, fmt.Errorf("hot set must be > 0 and <= 1: %v", hotKeys)
+		}
+		if hotReqs < 0 || hotReqs > 1 {
+			return nil, fmt.Errorf("hot set request share must be >= 0 and <= 1: %v", hotReqs)
+		}
+	default:
+		return nil, fmt.Errorf("unknown key distribution %q. Can be %s, %s, %s or %s", kind, KeyDistUniform, KeyDistZipf, KeyDistHotset, KeyDistSequential)
+	}
+	return &k, nil
+}
+
+// index returns the index of the next object to access out of n objects.
+// If k is nil objects are selected uniformly.
+func (k *KeyDist) index(rng *rand.Rand, n int) int {
+	if k == nil {
+		return rng.Intn(n)
+	}
+	switch k.kind {
+	case KeyDistZipf:
+		return k.zipfFor(n).RankN(rng, n)
+	case KeyDistHotset:
+		hot := int(math.Ceil(float64(n) * k.hotKeys))
+		if hot >= n {
+			return rng.Intn(n)
+		}
+		if rng.Float64() < k.hotReqs {
+			return rng.Intn(hot)
+		}
+		return hot + rng.Intn(n-hot)
+	case KeyDistSequential:
+		return int((atomic.AddUint64(&k.next, 1) - 1) % uint64(n))
+	}
+	return rng.Intn(n)
+}
+
+// zipfFor returns a Zipf sampler with at least n ranks.
+// The first n ranks of a bigger sampler have the same weights,
+// so the sampler is only replaced when the number of objects grows.
+func (k *KeyDist) zipfFor(n int) *model.Zipf {
+	if z, ok := k.zipf.Load().(*model.Zipf); ok && z.N() >= n {
+		return z
+	}
+	k.zipfMu.Lock()
+	defer k.zipfMu.Unlock()
+	size := n
+	if z, ok := k.zipf.Load().(*model.Zipf); ok {
+		if z.N() >= n {
+			return z
+		}
+		// Grow exponentially so a growing number of objects doesn't rebuild it every time.
+		if size < 2*z.N() {
+			size = 2 * z.N()
+		}
+	}
+	z := model.NewZipf(size, k.skew)
+	k.zipf.Store(z)
+	return z
+}
+
+// objectIndex returns the index of the next object to access out of n objects.
+func (c *Common) objectIndex(rng *rand.Rand, n int) int {
+	return c.KeyDist.index(rng, n)
+}
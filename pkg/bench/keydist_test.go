@@ -0,0 +1,81 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestKeyDist(t *testing.T) {
+	const n, samples = 100, 100000
+	rng := rand.New(rand.NewSource(0))
+
+	zipf, err := NewKeyDist(KeyDistZipf, 1, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Grow the number of objects while sampling.
+	zipf.index(rng, n/2)
+	counts := make([]int, n)
+	for i := 0; i < samples; i++ {
+		counts[zipf.index(rng, n)]++
+	}
+	// Rank 1 should be read twice as often as rank 2.
+	if ratio := float64(counts[0]) / float64(counts[1]); math.Abs(ratio-2) > 0.2 {
+		t.Errorf("zipf: rank 1/rank 2 ratio %.2f, want 2", ratio)
+	}
+	// Fewer objects than the sampler covers.
+	for i := 0; i < 1000; i++ {
+		if got := zipf.index(rng, 3); got >= 3 {
+			t.Fatalf("zipf: got index %d of 3 objects", got)
+		}
+	}
+
+	hot, err := NewKeyDist(KeyDistHotset, 0, 0.1, 0.9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hotReads int
+	for i := 0; i < samples; i++ {
+		if hot.index(rng, n) < n/10 {
+			hotReads++
+		}
+	}
+	if share := float64(hotReads) / samples; math.Abs(share-0.9) > 0.01 {
+		t.Errorf("hotset: %.3f of reads in hot set, want 0.9", share)
+	}
+
+	seq, err := NewKeyDist(KeyDistSequential, 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2*n; i++ {
+		if got := seq.index(rng, n); got != i%n {
+			t.Fatalf("sequential: got index %d, want %d", got, i%n)
+		}
+	}
+
+	if _, err := NewKeyDist("random", 0, 0, 0); err == nil {
+		t.Error("unknown distribution accepted")
+	}
+	if _, err := NewKeyDist(KeyDistHotset, 0, 0, 0.9); err == nil {
+		t.Error("empty hot set accepted")
+	}
+}
@@ -92,12 +92,14 @@ func (d *List) Prepare(ctx context.Context) error {
 					Thread:   uint16(i),
 					Size:     obj.Size,
 					File:     obj.Name,
+					Bucket:   d.bucketFor(obj.Prefix),
 					ObjPerOp: 1,
 					Endpoint: client.EndpointURL().String(),
 				}
 				opts.ContentType = obj.ContentType
 				op.Start = time.Now()
-				res, err := client.PutObject(ctx, d.Bucket, obj.Name, obj.Reader, obj.Size, opts)
+				// All objects with a prefix are stored in the same bucket, so they can be listed.
+				res, err := client.PutObject(ctx, op.Bucket, obj.Name, obj.Reader, obj.Size, opts)
 				op.End = time.Now()
 				if err != nil {
 					err := fmt.Errorf("upload error: %w", err)
@@ -107,6 +109,8 @@ func (d *List) Prepare(ctx context.Context) error {
 						groupErr = err
 					}
 					mu.Unlock()
+					op.Err = err.Error()
+					d.logOp(op, "")
 					return
 				}
 				obj.VersionID = res.VersionID
@@ -118,8 +122,11 @@ func (d *List) Prepare(ctx context.Context) error {
 						groupErr = err
 					}
 					mu.Unlock()
+					op.Err = err.Error()
+					d.logOp(op, obj.VersionID)
 					return
 				}
+				d.logOp(op, obj.VersionID)
 				cldone()
 				mu.Lock()
 				obj.Reader = nil
@@ -147,7 +154,7 @@ func (d *List) Prepare(ctx context.Context) error {
 func (d *List) Start(ctx context.Context, wait chan struct{}) (Operations, error) {
 	var wg sync.WaitGroup
 	wg.Add(d.Concurrency)
-	c := d.Collector
+	c := d.Collector.reopen()
 	if d.AutoTermDur > 0 {
 		ctx = c.AutoTerm(ctx, "LIST", d.AutoTermScale, autoTermCheck, autoTermSamples, d.AutoTermDur)
 	}
@@ -167,25 +174,26 @@ func (d *List) Start(ctx context.Context, wait chan struct{}) (Operations, error
 
 			<-wait
 			for {
-				select {
-				case <-done:
+				intended, ok := d.nextStart(i, done)
+				if !ok {
 					return
-				default:
 				}
 
 				prefix := objs[0].Prefix
 				client, cldone := d.Client()
 				op := Operation{
 					File:     prefix,
+					Bucket:   d.bucketFor(prefix),
 					OpType:   "LIST",
 					Thread:   uint16(i),
 					Size:     0,
 					Endpoint: client.EndpointURL().String(),
+					Intended: intended,
 				}
 				op.Start = time.Now()
 
 				// List all objects with prefix
-				listCh := client.ListObjects(nonTerm, d.Bucket, minio.ListObjectsOptions{WithMetadata: true, Prefix: objs[0].Prefix, Recursive: true})
+				listCh := client.ListObjects(nonTerm, op.Bucket, minio.ListObjectsOptions{WithMetadata: true, Prefix: objs[0].Prefix, Recursive: true})
 
 				// Wait for errCh to close.
 				for {
@@ -210,6 +218,7 @@ func (d *List) Start(ctx context.Context, wait chan struct{}) (Operations, error
 				}
 				op.End = time.Now()
 				cldone()
+				d.logOp(op, "")
 				rcv <- op
 			}
 		}(i)
@@ -30,7 +30,6 @@ import (
 	"time"
 
 	"github.com/minio/minio-go/v7"
-	"github.com/minio/minio/pkg/console"
 	"github.com/minio/warp/pkg/generator"
 )
 
@@ -39,6 +38,8 @@ type Mixed struct {
 	CreateObjects int
 	Collector     *Collector
 	Dist          *MixedDistribution
+	// Sequence is run by each thread instead of random operations when set.
+	Sequence *Sequence
 
 	GetOpts  minio.GetObjectOptions
 	StatOpts minio.StatObjectOptions
@@ -51,8 +52,14 @@ type MixedDistribution struct {
 	// Operation -> distribution.
 	Distribution map[string]float64
 	ops          []string
-	objects      map[string]generator.Object
-	rng          *rand.Rand
+	// objects are kept in a stable order, so key distributions can rank them.
+	objects generator.Objects
+	// index of each object in objects.
+	index map[string]int
+	// inUse counts the operations using each object.
+	// Objects in use are not deleted.
+	inUse map[string]int
+	rng   *rand.Rand
 
 	current int
 	mu      sync.Mutex
@@ -62,7 +69,9 @@ func (m *MixedDistribution) Generate(allocObjs int) error {
 	if m.Distribution[http.MethodDelete] > m.Distribution[http.MethodPut] {
 		return errors.New("DELETE distribution cannot be bigger than PUT")
 	}
-	m.objects = make(map[string]generator.Object, allocObjs)
+	m.objects = make(generator.Objects, 0, allocObjs)
+	m.index = make(map[string]int, allocObjs)
+	m.inUse = make(map[string]int)
 
 	err := m.normalize()
 	if err != nil {
@@ -85,10 +94,10 @@ func (m *MixedDistribution) Generate(allocObjs int) error {
 }
 
 func (m *MixedDistribution) Objects() generator.Objects {
-	res := make(generator.Objects, 0, len(m.objects))
-	for _, v := range m.objects {
-		res = append(res, v)
-	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	res := make(generator.Objects, len(m.objects))
+	copy(res, m.objects)
 	return res
 }
 
@@ -109,35 +118,58 @@ func (m *MixedDistribution) normalize() error {
 	return nil
 }
 
-func (m *MixedDistribution) randomObj() (obj generator.Object, done func()) {
+// randomObj returns an object selected by keys.
+// The object will not be deleted until done is called.
+func (m *MixedDistribution) randomObj(keys *KeyDist) (obj generator.Object, done func()) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	// Use map randomness to select.
-	for k, o := range m.objects {
-		delete(m.objects, k)
-		return o, func() {
-			m.mu.Lock()
-			m.objects[k] = obj
-			m.mu.Unlock()
+	if len(m.objects) == 0 {
+		panic("ran out of objects")
+	}
+	obj = m.objects[keys.index(m.rng, len(m.objects))]
+	m.inUse[obj.Name]++
+	return obj, func() {
+		m.mu.Lock()
+		if m.inUse[obj.Name]--; m.inUse[obj.Name] == 0 {
+			delete(m.inUse, obj.Name)
 		}
+		m.mu.Unlock()
 	}
-	panic("ran out of objects")
 }
 
+// deleteRandomObj removes a random object that is not in use.
+// The last object takes the place of the removed object.
 func (m *MixedDistribution) deleteRandomObj() generator.Object {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	// Use map randomness to select.
-	for k, o := range m.objects {
-		delete(m.objects, k)
-		return o
+	n := len(m.objects)
+	if n > 0 {
+		start := m.rng.Intn(n)
+		for i := 0; i < n; i++ {
+			o := m.objects[(start+i)%n]
+			if m.inUse[o.Name] > 0 {
+				continue
+			}
+			idx := m.index[o.Name]
+			last := m.objects[n-1]
+			m.objects[idx] = last
+			m.index[last.Name] = idx
+			m.objects = m.objects[:n-1]
+			delete(m.index, o.Name)
+			return o
+		}
 	}
 	panic("ran out of objects")
 }
 
 func (m *MixedDistribution) addObj(o generator.Object) {
 	m.mu.Lock()
-	m.objects[o.Name] = o
+	if idx, ok := m.index[o.Name]; ok {
+		m.objects[idx] = o
+	} else {
+		m.index[o.Name] = len(m.objects)
+		m.objects = append(m.objects, o)
+	}
 	m.mu.Unlock()
 }
 
@@ -152,71 +184,24 @@ func (m *MixedDistribution) getOp() string {
 // Prepare will create an empty bucket or delete any content already there
 // and upload a number of objects.
 func (g *Mixed) Prepare(ctx context.Context) error {
+	if g.Sequence != nil {
+		// Sequences upload their own objects.
+		g.Collector = NewCollector()
+		return g.createEmptyBucket(ctx)
+	}
 	if g.CreateObjects <= g.Concurrency {
 		return errors.New("initial number of objects should be at least matching concurrency")
 	}
 	if err := g.createEmptyBucket(ctx); err != nil {
 		return err
 	}
-	src := g.Source()
-	console.Info("\rUploading ", g.CreateObjects, " objects of ", src.String())
-	var wg sync.WaitGroup
-	wg.Add(g.Concurrency)
 	g.Collector = NewCollector()
-	obj := make(chan struct{}, g.CreateObjects)
-	for i := 0; i < g.CreateObjects; i++ {
-		obj <- struct{}{}
-	}
-	close(obj)
-	var groupErr error
-	var mu sync.Mutex
-	for i := 0; i < g.Concurrency; i++ {
-		go func(i int) {
-			defer wg.Done()
-			src := g.Source()
-			for range obj {
-				opts := g.PutOpts
-				done := ctx.Done()
-
-				select {
-				case <-done:
-					return
-				default:
-				}
-				obj := src.Object()
-				client, clDone := g.Client()
-				opts.ContentType = obj.ContentType
-				res, err := client.PutObject(ctx, g.Bucket, obj.Name, obj.Reader, obj.Size, opts)
-				if err != nil {
-					err := fmt.Errorf("upload error: %w", err)
-					g.Error(err)
-					mu.Lock()
-					if groupErr == nil {
-						groupErr = err
-					}
-					mu.Unlock()
-					return
-				}
-				obj.VersionID = res.VersionID
-				if res.Size != obj.Size {
-					err := fmt.Errorf("short upload. want: %d, got %d", obj.Size, res.Size)
-					g.Error(err)
-					mu.Lock()
-					if groupErr == nil {
-						groupErr = err
-					}
-					mu.Unlock()
-					return
-				}
-				clDone()
-				obj.Reader = nil
-				g.Dist.addObj(*obj)
-				g.prepareProgress(float64(len(g.Dist.objects)) / float64(g.CreateObjects))
-			}
-		}(i)
+	// Prepare uploads are not part of the mixed benchmark results.
+	objs, err := g.uploadObjects(ctx, g.CreateObjects, nil, nil)
+	for _, obj := range objs {
+		g.Dist.addObj(obj)
 	}
-	wg.Wait()
-	return groupErr
+	return err
 }
 
 // Start will execute the main benchmark.
@@ -224,7 +209,7 @@ func (g *Mixed) Prepare(ctx context.Context) error {
 func (g *Mixed) Start(ctx context.Context, wait chan struct{}) (Operations, error) {
 	var wg sync.WaitGroup
 	wg.Add(g.Concurrency)
-	c := g.Collector
+	c := g.Collector.reopen()
 	if g.AutoTermDur > 0 {
 		ctx = c.AutoTerm(ctx, "", g.AutoTermScale, autoTermCheck, autoTermSamples, g.AutoTermDur)
 	}
@@ -240,19 +225,23 @@ func (g *Mixed) Start(ctx context.Context, wait chan struct{}) (Operations, erro
 			putOpts := g.PutOpts
 			statOpts := g.StatOpts
 			getOpts := g.GetOpts
+			rng := rand.New(rand.NewSource(int64(i)))
 
 			<-wait
 			for {
-				select {
-				case <-done:
+				intended, ok := g.nextStart(i, done)
+				if !ok {
 					return
-				default:
+				}
+				if g.Sequence != nil {
+					g.runSequence(nonTerm, uint16(i), src, rng, intended, done, rcv)
+					continue
 				}
 				operation := g.Dist.getOp()
 				switch operation {
 				case http.MethodGet:
 					fbr := firstByteRecorder{}
-					obj, objDone := g.Dist.randomObj()
+					obj, objDone := g.Dist.randomObj(g.KeyDist)
 					client, clDone := g.Client()
 					op := Operation{
 						OpType:   operation,
@@ -261,25 +250,28 @@ func (g *Mixed) Start(ctx context.Context, wait chan struct{}) (Operations, erro
 						File:     obj.Name,
 						ObjPerOp: 1,
 						Endpoint: client.EndpointURL().String(),
+						Intended: intended,
 					}
 					op.Start = time.Now()
 					var err error
 					getOpts.VersionID = obj.VersionID
-					o, err := client.GetObject(nonTerm, g.Bucket, obj.Name, getOpts)
+					o, err := client.GetObject(nonTerm, g.bucketFor(obj.Name), obj.Name, getOpts)
 					fbr.r = o
 					if err != nil {
 						g.Error("download error:", err)
 						op.Err = err.Error()
 						op.End = time.Now()
+						g.logOp(op, obj.VersionID)
 						rcv <- op
 						clDone()
 						objDone()
 						continue
 					}
-					n, err := io.Copy(ioutil.Discard, &fbr)
+					n, err := io.Copy(ioutil.Discard, g.verifyReader(obj, &fbr, 0))
 					if err != nil {
 						g.Error("download error:", err)
 						op.Err = err.Error()
+						op.ErrKind = errKind(err)
 					}
 					op.FirstByte = fbr.t
 					op.End = time.Now()
@@ -287,6 +279,7 @@ func (g *Mixed) Start(ctx context.Context, wait chan struct{}) (Operations, erro
 						op.Err = fmt.Sprint("unexpected download size. want:", obj.Size, ", got:", n)
 						g.Error(op.Err)
 					}
+					g.logOp(op, obj.VersionID)
 					rcv <- op
 					objDone()
 					clDone()
@@ -303,9 +296,16 @@ func (g *Mixed) Start(ctx context.Context, wait chan struct{}) (Operations, erro
 						File:     obj.Name,
 						ObjPerOp: 1,
 						Endpoint: client.EndpointURL().String(),
+						Intended: intended,
+					}
+					sums, err := g.verifySums(obj)
+					if err != nil {
+						g.Error(err)
+						clDone()
+						continue
 					}
 					op.Start = time.Now()
-					res, err := client.PutObject(nonTerm, g.Bucket, obj.Name, obj.Reader, obj.Size, putOpts)
+					res, err := client.PutObject(nonTerm, g.bucketFor(obj.Name), obj.Name, obj.Reader, obj.Size, putOpts)
 					op.End = time.Now()
 					if err != nil {
 						g.Error("upload error:", err)
@@ -322,8 +322,10 @@ func (g *Mixed) Start(ctx context.Context, wait chan struct{}) (Operations, erro
 					}
 					clDone()
 					if op.Err == "" {
+						g.verifyAdd(*obj, sums)
 						g.Dist.addObj(*obj)
 					}
+					g.logOp(op, obj.VersionID)
 					rcv <- op
 				case http.MethodDelete:
 					client, clDone := g.Client()
@@ -335,18 +337,22 @@ func (g *Mixed) Start(ctx context.Context, wait chan struct{}) (Operations, erro
 						File:     obj.Name,
 						ObjPerOp: 1,
 						Endpoint: client.EndpointURL().String(),
+						Intended: intended,
 					}
 					op.Start = time.Now()
-					err := client.RemoveObject(nonTerm, g.Bucket, obj.Name, minio.RemoveObjectOptions{VersionID: obj.VersionID})
+					err := client.RemoveObject(nonTerm, g.bucketFor(obj.Name), obj.Name, minio.RemoveObjectOptions{VersionID: obj.VersionID})
 					op.End = time.Now()
 					clDone()
 					if err != nil {
 						g.Error("delete error: ", err)
 						op.Err = err.Error()
+					} else {
+						g.verifyRemove(obj)
 					}
+					g.logOp(op, obj.VersionID)
 					rcv <- op
 				case "STAT":
-					obj, objDone := g.Dist.randomObj()
+					obj, objDone := g.Dist.randomObj(g.KeyDist)
 					client, clDone := g.Client()
 					op := Operation{
 						OpType:   operation,
@@ -355,10 +361,11 @@ func (g *Mixed) Start(ctx context.Context, wait chan struct{}) (Operations, erro
 						File:     obj.Name,
 						ObjPerOp: 1,
 						Endpoint: client.EndpointURL().String(),
+						Intended: intended,
 					}
 					op.Start = time.Now()
 					var err error
-					objI, err := client.StatObject(nonTerm, g.Bucket, obj.Name, statOpts)
+					objI, err := client.StatObject(nonTerm, g.bucketFor(obj.Name), obj.Name, statOpts)
 					if err != nil {
 						g.Error("stat error: ", err)
 						op.Err = err.Error()
@@ -368,6 +375,7 @@ func (g *Mixed) Start(ctx context.Context, wait chan struct{}) (Operations, erro
 						op.Err = fmt.Sprint("unexpected stat size. want:", obj.Size, ", got:", objI.Size)
 						g.Error(op.Err)
 					}
+					g.logOp(op, obj.VersionID)
 					rcv <- op
 					objDone()
 					clDone()
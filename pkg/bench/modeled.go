@@ -0,0 +1,320 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/warp/pkg/generator"
+	"github.com/minio/warp/pkg/model"
+)
+
+// maxWrittenObjects is the maximum number of written objects
+// tracked for reads and deletes.
+const maxWrittenObjects = 100000
+
+// Modeled runs a workload described by a model.
+type Modeled struct {
+	Model *model.Model
+	// Scale multiplies the arrival rate of the model.
+	Scale float64
+	// CreateObjects is the number of objects to upload before starting.
+	// If 0 the number of objects read in the model is used.
+	CreateObjects int
+	Collector     *Collector
+
+	GetOpts  minio.GetObjectOptions
+	StatOpts minio.StatObjectOptions
+	Common
+
+	dist    *MixedDistribution
+	objects generator.Objects
+	zipf    *model.Zipf
+	written writtenObjects
+}
+
+// writtenObjects keeps track of objects uploaded during the benchmark,
+// ordered by upload time.
+type writtenObjects struct {
+	mu   sync.Mutex
+	objs []writtenObject
+}
+
+type writtenObject struct {
+	at  time.Time
+	obj generator.Object
+}
+
+func (w *writtenObjects) add(obj generator.Object) {
+	w.mu.Lock()
+	w.objs = append(w.objs, writtenObject{at: time.Now(), obj: obj})
+	if len(w.objs) > maxWrittenObjects {
+		w.objs = w.objs[len(w.objs)-maxWrittenObjects:]
+	}
+	w.mu.Unlock()
+}
+
+// writtenBefore returns the object written closest to d ago.
+func (w *writtenObjects) writtenBefore(d time.Duration) (generator.Object, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.objs) == 0 {
+		return generator.Object{}, false
+	}
+	at := time.Now().Add(-d)
+	i := sort.Search(len(w.objs), func(i int) bool {
+		return !w.objs[i].at.Before(at)
+	})
+	if i == len(w.objs) {
+		i--
+	}
+	return w.objs[i].obj, true
+}
+
+// remove removes a random object.
+func (w *writtenObjects) remove(rng *rand.Rand) (generator.Object, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.objs) == 0 {
+		return generator.Object{}, false
+	}
+	i := rng.Intn(len(w.objs))
+	obj := w.objs[i].obj
+	w.objs = append(w.objs[:i], w.objs[i+1:]...)
+	return obj, true
+}
+
+// Prepare will create an empty bucket or delete any content already there
+// and upload the objects read by the model.
+func (g *Modeled) Prepare(ctx context.Context) error {
+	m := g.Model
+	if err := m.Validate(); err != nil {
+		return err
+	}
+	if g.Scale <= 0 {
+		return errors.New("scale must be > 0")
+	}
+	g.dist = &MixedDistribution{Distribution: make(map[string]float64, len(m.Ops))}
+	for method, op := range m.Ops {
+		if method == http.MethodHead {
+			method = "STAT"
+		}
+		g.dist.Distribution[method] = op.Share
+	}
+	// Objects can only be deleted after they have been written.
+	if g.dist.Distribution[http.MethodDelete] > g.dist.Distribution[http.MethodPut] {
+		g.dist.Distribution[http.MethodDelete] = g.dist.Distribution[http.MethodPut]
+	}
+	if err := g.dist.Generate(0); err != nil {
+		return err
+	}
+	if g.CreateObjects <= 0 {
+		g.CreateObjects = m.Popularity.Keys
+	}
+	if g.CreateObjects <= 0 {
+		g.CreateObjects = g.Concurrency
+	}
+	sizes := g.opSizes(http.MethodGet)
+	if len(sizes) == 0 {
+		sizes = g.opSizes(http.MethodPut)
+	}
+
+	if err := g.createEmptyBucket(ctx); err != nil {
+		return err
+	}
+	g.Collector = NewCollector()
+	objs, err := g.uploadObjects(ctx, g.CreateObjects, g.Collector, sizes)
+	g.objects = objs
+	g.zipf = model.NewZipf(len(g.objects), m.Popularity.Skew)
+	return err
+}
+
+// opSizes returns the object sizes of a method.
+func (g *Modeled) opSizes(method string) model.Histogram {
+	if op := g.Model.Ops[method]; op != nil {
+		return op.Sizes
+	}
+	return nil
+}
+
+// Start will execute the main benchmark.
+// Requests arrive with the rate of the model multiplied by the scale,
+// but are delayed if all concurrent operations are busy.
+// Operations should begin executing when the start channel is closed.
+func (g *Modeled) Start(ctx context.Context, wait chan struct{}) (Operations, error) {
+	var wg sync.WaitGroup
+	wg.Add(g.Concurrency)
+	c := g.Collector.reopen()
+	reqs := make(chan time.Time)
+
+	// Non-terminating context.
+	nonTerm := context.Background()
+
+	for i := 0; i < g.Concurrency; i++ {
+		go func(i int) {
+			rcv := c.Receiver()
+			defer wg.Done()
+			src := g.Source()
+			rng := rand.New(rand.NewSource(int64(i)))
+			for due := range reqs {
+				op, ok := g.run(nonTerm, uint16(i), src, rng)
+				if !ok {
+					continue
+				}
+				// Latency includes time waiting for a free thread.
+				op.Intended = due
+				g.logOp(op, "")
+				rcv <- op
+			}
+		}(i)
+	}
+
+	done := ctx.Done()
+	rng := rand.New(rand.NewSource(0))
+	timer := time.NewTimer(0)
+	<-timer.C
+	<-wait
+	start := time.Now()
+	var next time.Duration
+dispatch:
+	for {
+		// Arrivals are a Poisson process with the rate of the current interval.
+		rate := g.Model.Rate.RateAt(next) * g.Scale
+		if rate <= 0 {
+			next = (next/g.Model.Rate.Interval + 1) * g.Model.Rate.Interval
+			continue
+		}
+		next += time.Duration(rng.ExpFloat64() / rate * float64(time.Second))
+		if d := time.Until(start.Add(next)); d > 0 {
+			timer.Reset(d)
+			select {
+			case <-done:
+				timer.Stop()
+				break dispatch
+			case <-timer.C:
+			}
+		}
+		select {
+		case <-done:
+			break dispatch
+		case reqs <- start.Add(next):
+		}
+	}
+	close(reqs)
+	wg.Wait()
+	return c.Close(), nil
+}
+
+// readObject returns an object to read.
+// Recently written objects are read according to the read-after-write model,
+// otherwise objects are chosen by popularity.
+func (g *Modeled) readObject(rng *rand.Rand) generator.Object {
+	raw := g.Model.ReadAfterWrite
+	if raw.Ratio > 0 && rng.Float64() < raw.Ratio {
+		if obj, ok := g.written.writtenBefore(time.Duration(raw.Delay.Sample(rng))); ok {
+			return obj
+		}
+	}
+	return g.objects[g.zipf.Rank(rng)]
+}
+
+// run executes a single operation chosen by the model.
+// If there is nothing to delete no operation is executed and false is returned.
+func (g *Modeled) run(ctx context.Context, thread uint16, src generator.Source, rng *rand.Rand) (Operation, bool) {
+	operation := g.dist.getOp()
+	var obj generator.Object
+	switch operation {
+	case http.MethodGet, "STAT":
+		obj = g.readObject(rng)
+	case http.MethodPut:
+		o := src.Object()
+		o.Size = g.opSizes(http.MethodPut).Sample(rng)
+		obj = *o
+	case http.MethodDelete:
+		var ok bool
+		if obj, ok = g.written.remove(rng); !ok {
+			return Operation{}, false
+		}
+	}
+	client, cldone := g.Client()
+	defer cldone()
+	op := Operation{
+		OpType:   operation,
+		Thread:   thread,
+		File:     obj.Name,
+		ObjPerOp: 1,
+		Endpoint: client.EndpointURL().String(),
+	}
+	op.Start = time.Now()
+	var err error
+	switch operation {
+	case http.MethodGet:
+		fbr := firstByteRecorder{}
+		opts := g.GetOpts
+		opts.VersionID = obj.VersionID
+		var o *minio.Object
+		o, err = client.GetObject(ctx, g.bucketFor(obj.Name), obj.Name, opts)
+		if err == nil {
+			fbr.r = o
+			op.Size, err = io.Copy(ioutil.Discard, &fbr)
+			op.FirstByte = fbr.t
+			o.Close()
+		}
+		if err == nil && op.Size != obj.Size {
+			err = fmt.Errorf("unexpected download size. want: %d, got: %d", obj.Size, op.Size)
+		}
+	case "STAT":
+		opts := g.StatOpts
+		opts.VersionID = obj.VersionID
+		_, err = client.StatObject(ctx, g.bucketFor(obj.Name), obj.Name, opts)
+	case http.MethodPut:
+		opts := g.PutOpts
+		opts.ContentType = obj.ContentType
+		op.Size = obj.Size
+		var res minio.UploadInfo
+		res, err = client.PutObject(ctx, g.bucketFor(obj.Name), obj.Name, io.LimitReader(obj.Reader, obj.Size), obj.Size, opts)
+		if err == nil {
+			obj.Reader = nil
+			obj.VersionID = res.VersionID
+			g.written.add(obj)
+		}
+	case http.MethodDelete:
+		err = client.RemoveObject(ctx, g.bucketFor(obj.Name), obj.Name, minio.RemoveObjectOptions{VersionID: obj.VersionID})
+	}
+	op.End = time.Now()
+	if err != nil {
+		g.Error(operation, " ", obj.Name, ": ", err)
+		op.Err = err.Error()
+	}
+	return op, true
+}
+
+// Cleanup deletes everything uploaded to the bucket.
+func (g *Modeled) Cleanup(ctx context.Context) {
+	g.deleteAllInBucket(ctx)
+}
@@ -0,0 +1,253 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// Operation types recorded by the multipart benchmark.
+const (
+	OpCreateMultipart   = "CREATE_MULTIPART"
+	OpPutPart           = "PUT_PART"
+	OpCompleteMultipart = "COMPLETE_MULTIPART"
+	OpAbortMultipart    = "ABORT_MULTIPART"
+)
+
+// Multipart benchmarks multipart uploads.
+// Every phase of an upload is recorded as a separate operation.
+type Multipart struct {
+	Common
+	// PartSize is the size of each part. The last part of an object may be smaller.
+	PartSize int64
+	// PartsInFlight is the number of parts of an object uploaded concurrently.
+	PartsInFlight int
+	// AbortRatio is the fraction of uploads aborted instead of completed.
+	AbortRatio float64
+
+	prefixes uploadedPrefixes
+
+	// Uploads that have not been completed or aborted, upload ID -> object name.
+	mu      sync.Mutex
+	pending map[string]string
+}
+
+// Prepare will create an empty bucket or delete any content already there.
+func (u *Multipart) Prepare(ctx context.Context) error {
+	if u.PartSize <= 0 {
+		return errors.New("part size must be > 0")
+	}
+	if u.PartsInFlight <= 0 {
+		return errors.New("parts in flight must be > 0")
+	}
+	if u.AbortRatio < 0 || u.AbortRatio > 1 {
+		return errors.New("abort ratio must be between 0 and 1")
+	}
+	u.pending = make(map[string]string)
+	return u.createEmptyBucket(ctx)
+}
+
+// Start will execute the main benchmark.
+// Operations should begin executing when the start channel is closed.
+func (u *Multipart) Start(ctx context.Context, wait chan struct{}) (Operations, error) {
+	var wg sync.WaitGroup
+	wg.Add(u.Concurrency)
+	c := NewCollector()
+	if u.AutoTermDur > 0 {
+		ctx = c.AutoTerm(ctx, OpPutPart, u.AutoTermScale, autoTermCheck, autoTermSamples, u.AutoTermDur)
+	}
+
+	// Non-terminating context.
+	nonTerm := context.Background()
+
+	for i := 0; i < u.Concurrency; i++ {
+		src := u.Source()
+		go func(i int) {
+			rcv := c.Receiver()
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(int64(i)))
+			done := ctx.Done()
+
+			<-wait
+			for {
+				intended, ok := u.nextStart(i, done)
+				if !ok {
+					return
+				}
+				obj := src.Object()
+				u.prefixes.add(obj.Prefix)
+				b, err := ioutil.ReadAll(obj.Reader)
+				if err != nil {
+					u.Error("generator error: ", err)
+					continue
+				}
+				opts := u.PutOpts
+				opts.ContentType = obj.ContentType
+				abort := u.AbortRatio > 0 && rng.Float64() < u.AbortRatio
+				client, cldone := u.Client()
+				u.upload(nonTerm, minio.Core{Client: client}, uint16(i), obj.Name, b, opts, abort, intended, rcv)
+				cldone()
+			}
+		}(i)
+	}
+	wg.Wait()
+	return c.Close(), nil
+}
+
+// upload uploads data as a multipart object and sends every operation to rcv.
+// If abort is set the upload is aborted when all parts have been uploaded.
+// intended is the scheduled start of the upload, if any.
+func (u *Multipart) upload(ctx context.Context, core minio.Core, thread uint16, name string, data []byte, opts minio.PutObjectOptions, abort bool, intended time.Time, rcv chan<- Operation) {
+	endpoint := core.EndpointURL().String()
+	newOp := func(opType string, size int64) Operation {
+		return Operation{
+			OpType:   opType,
+			Thread:   thread,
+			Size:     size,
+			File:     name,
+			ObjPerOp: 1,
+			Endpoint: endpoint,
+		}
+	}
+	send := func(op Operation) {
+		u.logOp(op, "")
+		rcv <- op
+	}
+
+	op := newOp(OpCreateMultipart, 0)
+	op.Intended = intended
+	op.Start = time.Now()
+	uploadID, err := core.NewMultipartUpload(ctx, u.bucketFor(name), name, opts)
+	op.End = time.Now()
+	if err != nil {
+		u.Error("create multipart error: ", err)
+		op.Err = err.Error()
+		send(op)
+		return
+	}
+	send(op)
+	u.mu.Lock()
+	u.pending[uploadID] = name
+	u.mu.Unlock()
+
+	size := int64(len(data))
+	nParts := int((size + u.PartSize - 1) / u.PartSize)
+	if nParts == 0 {
+		// Empty objects are uploaded as a single empty part.
+		nParts = 1
+	}
+	parts := make([]minio.CompletePart, nParts)
+	partIdx := make(chan int, nParts)
+	for i := range parts {
+		partIdx <- i
+	}
+	close(partIdx)
+	workers := u.PartsInFlight
+	if workers > nParts {
+		workers = nParts
+	}
+	var failed int32
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range partIdx {
+				start := int64(i) * u.PartSize
+				end := start + u.PartSize
+				if end > size {
+					end = size
+				}
+				op := newOp(OpPutPart, end-start)
+				op.Start = time.Now()
+				part, err := core.PutObjectPart(ctx, u.bucketFor(name), name, uploadID, i+1, bytes.NewReader(data[start:end]), end-start, "", "", opts.ServerSideEncryption)
+				op.End = time.Now()
+				if err != nil {
+					u.Error("upload part error: ", err)
+					op.Err = err.Error()
+					atomic.StoreInt32(&failed, 1)
+				}
+				parts[i] = minio.CompletePart{PartNumber: part.PartNumber, ETag: part.ETag}
+				send(op)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if abort || atomic.LoadInt32(&failed) != 0 {
+		op := newOp(OpAbortMultipart, 0)
+		op.Start = time.Now()
+		err := core.AbortMultipartUpload(ctx, u.bucketFor(name), name, uploadID)
+		op.End = time.Now()
+		if err != nil {
+			u.Error("abort multipart error: ", err)
+			op.Err = err.Error()
+		} else {
+			u.removePending(uploadID)
+		}
+		send(op)
+		return
+	}
+
+	op = newOp(OpCompleteMultipart, 0)
+	op.Start = time.Now()
+	_, err = core.CompleteMultipartUpload(ctx, u.bucketFor(name), name, uploadID, parts)
+	op.End = time.Now()
+	if err != nil {
+		u.Error("complete multipart error: ", err)
+		op.Err = err.Error()
+	} else {
+		u.removePending(uploadID)
+	}
+	send(op)
+}
+
+func (u *Multipart) removePending(uploadID string) {
+	u.mu.Lock()
+	delete(u.pending, uploadID)
+	u.mu.Unlock()
+}
+
+// Cleanup aborts uploads that were not completed and deletes everything uploaded to the bucket.
+func (u *Multipart) Cleanup(ctx context.Context) {
+	cl, done := u.Client()
+	core := minio.Core{Client: cl}
+	u.mu.Lock()
+	for uploadID, name := range u.pending {
+		if err := core.AbortMultipartUpload(ctx, u.bucketFor(name), name, uploadID); err != nil {
+			if minio.ToErrorResponse(err).StatusCode != http.StatusNotFound {
+				u.Error("abort multipart error: ", err)
+			}
+		}
+		delete(u.pending, uploadID)
+	}
+	u.mu.Unlock()
+	done()
+
+	u.prefixes.deleteAll(ctx, &u.Common)
+}
@@ -19,11 +19,14 @@ package bench
 
 import (
 	"bufio"
+	"container/heap"
 	"context"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
 	"math"
+	"net/http"
 	"sort"
 	"strconv"
 	"strings"
@@ -32,6 +35,7 @@ import (
 
 	"github.com/dustin/go-humanize"
 	"github.com/minio/minio/pkg/console"
+	"github.com/minio/warp/pkg/accesslog"
 )
 
 type Operations []Operation
@@ -43,20 +47,31 @@ type Operation struct {
 	FirstByte *time.Time `json:"first_byte"`
 	End       time.Time  `json:"end"`
 	Err       string     `json:"err"`
+	ErrKind   ErrKind    `json:"err_kind,omitempty"`
 	Size      int64      `json:"size"`
 	File      string     `json:"file"`
 	Thread    uint16     `json:"thread"`
 	ClientID  string     `json:"client_id"`
 	Endpoint  string     `json:"endpoint"`
+	SLO       SLOVerdict `json:"slo,omitempty"`
+	Bucket    string     `json:"bucket,omitempty"`
+	// Intended is the scheduled start of the operation when running at a fixed rate.
+	// Start is when the request was actually sent.
+	Intended time.Time `json:"intended,omitempty"`
+	// Step is the sweep step the operation was run in.
+	Step string `json:"step,omitempty"`
+	// Phase is the scenario phase the operation was run in.
+	Phase string `json:"phase,omitempty"`
 }
 
 type Collector struct {
 	ops Operations
 	// The mutex protects the ops above.
 	// Once ops have been added, they should no longer be modified.
-	opsMu sync.Mutex
-	rcv   chan Operation
-	rcvWg sync.WaitGroup
+	opsMu  sync.Mutex
+	rcv    chan Operation
+	rcvWg  sync.WaitGroup
+	closed bool
 }
 
 func NewCollector() *Collector {
@@ -157,12 +172,44 @@ func (c *Collector) Receiver() chan<- Operation {
 func (c *Collector) Close() Operations {
 	close(c.rcv)
 	c.rcvWg.Wait()
+	c.closed = true
 	return c.ops
 }
 
-// Duration returns the duration o.End-o.Start
+// reopen returns c, or a new collector if c has been closed.
+// This allows a benchmark to be started more than once.
+func (c *Collector) reopen() *Collector {
+	if c.closed {
+		return NewCollector()
+	}
+	return c
+}
+
+// Duration returns the latency of the operation.
+// This is measured from the intended start if the operation was scheduled,
+// so time waiting to be sent is included.
 func (o Operation) Duration() time.Duration {
-	return o.End.Sub(o.Start)
+	return o.End.Sub(o.latencyStart())
+}
+
+// Prefix returns the prefix of the object, which is the object name up to the first '/'.
+// Operations on a prefix, like listings, return the prefix itself.
+func (o Operation) Prefix() string {
+	if o.OpType == "LIST" {
+		return o.File
+	}
+	if i := strings.IndexByte(o.File, '/'); i >= 0 {
+		return o.File[:i]
+	}
+	return ""
+}
+
+// latencyStart returns the time latency is measured from.
+func (o Operation) latencyStart() time.Time {
+	if !o.Intended.IsZero() && o.Intended.Before(o.Start) {
+		return o.Intended
+	}
+	return o.Start
 }
 
 // Throughput is the throughput as bytes/second.
@@ -284,7 +331,7 @@ func (o Operation) TTFB() time.Duration {
 	if o.FirstByte == nil {
 		return 0
 	}
-	return o.FirstByte.Sub(o.Start)
+	return o.FirstByte.Sub(o.latencyStart())
 }
 
 // SortByStartTime will sort the operations by start time.
@@ -304,8 +351,7 @@ func (o Operations) SortByStartTime() {
 // Fastest operations first.
 func (o Operations) SortByDuration() {
 	sort.Slice(o, func(i, j int) bool {
-		a, b := o[i].End.Sub(o[i].Start), o[j].End.Sub(o[j].Start)
-		return a < b
+		return o[i].Duration() < o[j].Duration()
 	})
 }
 
@@ -381,6 +427,28 @@ func (o Operations) FilterByOp(opType string) Operations {
 	return dst
 }
 
+// IsDelayOp returns whether operations of the type measure the delay
+// before a write was observed rather than a request.
+func IsDelayOp(opType string) bool {
+	switch opType {
+	case OpVisibility, OpReplicatePut, OpReplicateDelete:
+		return true
+	}
+	return false
+}
+
+// FilterRequests returns operations that are requests.
+// Sequences and delays are measured by other operations and are removed.
+func (o Operations) FilterRequests() Operations {
+	dst := make(Operations, 0, len(o))
+	for _, o := range o {
+		if o.OpType != OpSequence && !IsDelayOp(o.OpType) {
+			dst = append(dst, o)
+		}
+	}
+	return dst
+}
+
 // SetClientID will set the client ID for all operations.
 func (o Operations) SetClientID(id string) {
 	for i := range o {
@@ -400,6 +468,103 @@ func (o Operations) FilterByEndpoint(endpoint string) Operations {
 	return dst
 }
 
+// FilterByBucket returns operations run against a specific bucket.
+// Always returns a copy.
+func (o Operations) FilterByBucket(bucket string) Operations {
+	dst := make(Operations, 0, len(o))
+	for _, o := range o {
+		if o.Bucket == bucket {
+			dst = append(dst, o)
+		}
+	}
+	return dst
+}
+
+// SetBuckets sets the bucket of operations without one, using the bucket of the object.
+func (o Operations) SetBuckets(b *BucketSet) {
+	if b == nil {
+		return
+	}
+	for i, op := range o {
+		if op.Bucket == "" && op.File != "" {
+			o[i].Bucket = b.For(op.File)
+		}
+	}
+}
+
+// SetStep sets the sweep step of all operations started at or after from.
+func (o Operations) SetStep(step string, from time.Time) {
+	for i := range o {
+		if !o[i].Start.Before(from) {
+			o[i].Step = step
+		}
+	}
+}
+
+// FilterByStep returns operations run in the specified sweep step.
+func (o Operations) FilterByStep(step string) Operations {
+	dst := make(Operations, 0, len(o))
+	for _, op := range o {
+		if op.Step == step {
+			dst = append(dst, op)
+		}
+	}
+	return dst
+}
+
+// Steps returns the sweep steps in the order they were run.
+// Operations without a step are ignored.
+func (o Operations) Steps() []string {
+	return o.inStartOrder(func(op Operation) string { return op.Step })
+}
+
+// SetPhase sets the scenario phase of all operations.
+func (o Operations) SetPhase(phase string) {
+	for i := range o {
+		o[i].Phase = phase
+	}
+}
+
+// FilterByPhase returns operations run in the specified scenario phase.
+func (o Operations) FilterByPhase(phase string) Operations {
+	dst := make(Operations, 0, len(o))
+	for _, op := range o {
+		if op.Phase == phase {
+			dst = append(dst, op)
+		}
+	}
+	return dst
+}
+
+// Phases returns the scenario phases in the order they were run.
+// Operations without a phase are ignored.
+func (o Operations) Phases() []string {
+	return o.inStartOrder(func(op Operation) string { return op.Phase })
+}
+
+// inStartOrder returns the distinct non-empty values of key,
+// ordered by the first operation started with each value.
+func (o Operations) inStartOrder(key func(op Operation) string) []string {
+	first := make(map[string]time.Time)
+	for _, op := range o {
+		k := key(op)
+		if k == "" {
+			continue
+		}
+		if t, ok := first[k]; !ok || op.Start.Before(t) {
+			first[k] = op.Start
+		}
+	}
+	keys := make([]string, 0, len(first))
+	for k := range first {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return first[keys[i]].Before(first[keys[j]])
+	})
+	return keys
+}
+
 // ByOp separates the operations by op.
 func (o Operations) ByOp() map[string]Operations {
 	dst := make(map[string]Operations, 1)
@@ -689,6 +854,28 @@ func (o Operations) SplitSizes(minShare float64) []SizeSegment {
 	return res
 }
 
+// SplitSizesAt will return data split at the specified size boundaries.
+// Each segment contains operations from one boundary up to, but not including, the next.
+// Empty segments are not returned.
+func (o Operations) SplitSizesAt(bounds []int64) []SizeSegment {
+	var res []SizeSegment
+	for i := 0; i+1 < len(bounds); i++ {
+		seg := SizeSegment{
+			Smallest: bounds[i],
+			Biggest:  bounds[i+1],
+		}
+		for _, op := range o {
+			if op.Size >= seg.Smallest && op.Size < seg.Biggest {
+				seg.Ops = append(seg.Ops, op)
+			}
+		}
+		if len(seg.Ops) > 0 {
+			res = append(res, seg)
+		}
+	}
+	return res
+}
+
 // Duration returns the full duration from start of first operation to end of the last.
 func (o Operations) Duration() time.Duration {
 	start, end := o.TimeRange()
@@ -857,6 +1044,33 @@ func (o Operations) Endpoints() []string {
 	return dst
 }
 
+// Buckets returns the buckets operations were run against as a sorted slice.
+// Operations without a bucket are ignored.
+func (o Operations) Buckets() []string {
+	buckets := make(map[string]struct{})
+	for _, op := range o {
+		if op.Bucket != "" {
+			buckets[op.Bucket] = struct{}{}
+		}
+	}
+	dst := make([]string, 0, len(buckets))
+	for k := range buckets {
+		dst = append(dst, k)
+	}
+	sort.Strings(dst)
+	return dst
+}
+
+// ByPrefix returns the operations grouped by the prefix of the objects operated on.
+func (o Operations) ByPrefix() map[string]Operations {
+	dst := make(map[string]Operations)
+	for _, op := range o {
+		p := op.Prefix()
+		dst[p] = append(dst[p], op)
+	}
+	return dst
+}
+
 // Errors returns the errors found.
 func (o Operations) Errors() []string {
 	if len(o) == 0 {
@@ -934,16 +1148,19 @@ func (o Operations) FilterErrors() Operations {
 // The comment, if any, is written at the end of the file, each line prefixed with '# '.
 func (o Operations) CSV(w io.Writer, comment string) error {
 	bw := bufio.NewWriter(w)
-	_, err := bw.WriteString("idx\tthread\top\tclient_id\tn_objects\tbytes\tendpoint\tfile\terror\tstart\tfirst_byte\tend\tduration_ns\n")
+	_, err := bw.WriteString("idx\tthread\top\tclient_id\tn_objects\tbytes\tendpoint\tfile\terror\tstart\tfirst_byte\tend\tduration_ns\tslo\terr_kind\tbucket\tintended\tstep\tphase\n")
 	if err != nil {
 		return err
 	}
 	for i, op := range o {
-		var ttfb string
+		var ttfb, intended string
 		if op.FirstByte != nil {
 			ttfb = op.FirstByte.Format(time.RFC3339Nano)
 		}
-		_, err := fmt.Fprintf(bw, "%d\t%d\t%s\t%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%d\n", i, op.Thread, op.OpType, op.ClientID, op.ObjPerOp, op.Size, csvEscapeString(op.Endpoint), op.File, csvEscapeString(op.Err), op.Start.Format(time.RFC3339Nano), ttfb, op.End.Format(time.RFC3339Nano), op.End.Sub(op.Start)/time.Nanosecond)
+		if !op.Intended.IsZero() {
+			intended = op.Intended.Format(time.RFC3339Nano)
+		}
+		_, err := fmt.Fprintf(bw, "%d\t%d\t%s\t%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\n", i, op.Thread, op.OpType, op.ClientID, op.ObjPerOp, op.Size, csvEscapeString(op.Endpoint), csvEscapeString(op.File), csvEscapeString(op.Err), op.Start.Format(time.RFC3339Nano), ttfb, op.End.Format(time.RFC3339Nano), op.Duration()/time.Nanosecond, op.SLO, op.ErrKind, op.Bucket, intended, csvEscapeString(op.Step), csvEscapeString(op.Phase))
 		if err != nil {
 			return err
 		}
@@ -994,15 +1211,27 @@ func OperationsFromCSV(r io.Reader, analyzeOnly bool, offset, limit int, log fun
 	}
 	if analyzeOnly {
 		// When analyzing map file names to a number for less RAM.
+		// The prefix is kept, so operations can be analyzed by prefix.
 		var i int
 		m := make(map[string]int)
+		prefixes := make(map[string]string)
 		fileMap = func(s string) string {
-			if v, ok := m[s]; ok {
+			v, ok := m[s]
+			if !ok {
+				i++
+				m[s] = i
+				v = i
+			}
+			idx := strings.IndexByte(s, '/')
+			if idx < 0 {
 				return strconv.Itoa(v)
 			}
-			i++
-			m[s] = i
-			return strconv.Itoa(i)
+			prefix, ok := prefixes[s[:idx]]
+			if !ok {
+				prefix = string([]byte(s[:idx]))
+				prefixes[prefix] = prefix
+			}
+			return prefix + "/" + strconv.Itoa(v)
 		}
 	}
 	for {
@@ -1055,7 +1284,37 @@ func OperationsFromCSV(r io.Reader, analyzeOnly bool, offset, limit int, log fun
 		if idx, ok := fieldIdx["client_id"]; ok {
 			clientID = values[idx]
 		}
-		file := fileMap(values[fieldIdx["file"]])
+		var slo SLOVerdict
+		if idx, ok := fieldIdx["slo"]; ok {
+			slo = SLOVerdict(values[idx])
+		}
+		var errKind ErrKind
+		if idx, ok := fieldIdx["err_kind"]; ok {
+			errKind = ErrKind(values[idx])
+		}
+		var bucket string
+		if idx, ok := fieldIdx["bucket"]; ok {
+			bucket = values[idx]
+		}
+		var step, phase string
+		if idx, ok := fieldIdx["step"]; ok {
+			step = values[idx]
+		}
+		if idx, ok := fieldIdx["phase"]; ok {
+			phase = values[idx]
+		}
+		var intended time.Time
+		if idx, ok := fieldIdx["intended"]; ok && values[idx] != "" {
+			intended, err = time.Parse(time.RFC3339Nano, values[idx])
+			if err != nil {
+				return nil, err
+			}
+		}
+		file := values[fieldIdx["file"]]
+		if op := values[fieldIdx["op"]]; op != "LIST" {
+			// LIST operations store the listed prefix.
+			file = fileMap(file)
+		}
 
 		ops = append(ops, Operation{
 			OpType:    values[fieldIdx["op"]],
@@ -1064,11 +1323,17 @@ func OperationsFromCSV(r io.Reader, analyzeOnly bool, offset, limit int, log fun
 			FirstByte: ttfb,
 			End:       end,
 			Err:       values[fieldIdx["error"]],
+			ErrKind:   errKind,
+			Bucket:    bucket,
 			Size:      size,
 			File:      file,
 			Thread:    uint16(thread),
 			Endpoint:  endpoint,
 			ClientID:  getClient(clientID),
+			SLO:       slo,
+			Intended:  intended,
+			Step:      step,
+			Phase:     phase,
 		})
 		if log != nil && len(ops)%1000000 == 0 {
 			log("\r%d operations loaded...", len(ops))
@@ -1082,3 +1347,80 @@ func OperationsFromCSV(r io.Reader, analyzeOnly bool, offset, limit int, log fun
 	}
 	return ops, nil
 }
+
+// OperationsFromAccessLog converts access log entries to operations.
+// The client IP of each request is used as client ID.
+// Threads are assigned so operations on a thread never overlap,
+// so the thread count reflects the concurrency of each client.
+// Operations are returned sorted by start time.
+func OperationsFromAccessLog(entries accesslog.Entries) (Operations, error) {
+	entries.SortByTime()
+	ops := make(Operations, 0, len(entries))
+	clients := make(map[string]*threadEnds)
+	nextThread := 0
+	for _, e := range entries {
+		op := Operation{
+			OpType:   e.Method,
+			ObjPerOp: 1,
+			Start:    e.Time,
+			End:      e.Time.Add(e.Latency),
+			Size:     e.Size,
+			File:     e.Key,
+			ClientID: e.ClientIP,
+			Endpoint: e.Endpoint,
+		}
+		switch e.Method {
+		case http.MethodHead:
+			op.OpType = "STAT"
+			op.Size = 0
+		case http.MethodDelete:
+			op.Size = 0
+		}
+		if e.Err {
+			op.Err = "request failed"
+			if e.Status != 0 {
+				op.Err = fmt.Sprintf("status code %d", e.Status)
+			}
+		}
+
+		// Reuse the thread of the client that finished first, if it is done.
+		th := clients[e.ClientIP]
+		if th == nil {
+			th = &threadEnds{}
+			clients[e.ClientIP] = th
+		}
+		if th.Len() > 0 && !(*th)[0].end.After(op.Start) {
+			op.Thread = (*th)[0].thread
+			(*th)[0].end = op.End
+			heap.Fix(th, 0)
+		} else {
+			if nextThread > math.MaxUint16 {
+				return nil, errors.New("too many concurrent requests in access log")
+			}
+			op.Thread = uint16(nextThread)
+			nextThread++
+			heap.Push(th, threadEnd{thread: op.Thread, end: op.End})
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+type threadEnd struct {
+	thread uint16
+	end    time.Time
+}
+
+// threadEnds is a min-heap of threads ordered by the end of their last operation.
+type threadEnds []threadEnd
+
+func (t threadEnds) Len() int            { return len(t) }
+func (t threadEnds) Less(i, j int) bool  { return t[i].end.Before(t[j].end) }
+func (t threadEnds) Swap(i, j int)       { t[i], t[j] = t[j], t[i] }
+func (t *threadEnds) Push(x interface{}) { *t = append(*t, x.(threadEnd)) }
+func (t *threadEnds) Pop() interface{} {
+	old := *t
+	x := old[len(old)-1]
+	*t = old[:len(old)-1]
+	return x
+}
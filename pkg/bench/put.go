@@ -31,16 +31,11 @@ import (
 // Put benchmarks upload speed.
 type Put struct {
 	Common
-	prefixes map[string]struct{}
-	LogPath  string
+	prefixes uploadedPrefixes
 }
 
 // Prepare will create an empty bucket ot delete any content already there.
 func (u *Put) Prepare(ctx context.Context) error {
-	e := u.createAccessLog(ctx, u.LogPath)
-	if e != nil {
-		return e
-	}
 	return u.createEmptyBucket(ctx)
 }
 
@@ -53,14 +48,12 @@ func (u *Put) Start(ctx context.Context, wait chan struct{}) (Operations, error)
 	if u.AutoTermDur > 0 {
 		ctx = c.AutoTerm(ctx, http.MethodPut, u.AutoTermScale, autoTermCheck, autoTermSamples, u.AutoTermDur)
 	}
-	u.prefixes = make(map[string]struct{}, u.Concurrency)
 
 	// Non-terminating context.
 	nonTerm := context.Background()
 
 	for i := 0; i < u.Concurrency; i++ {
 		src := u.Source()
-		u.prefixes[src.Prefix()] = struct{}{}
 		go func(i int) {
 			rcv := c.Receiver()
 			defer wg.Done()
@@ -69,12 +62,12 @@ func (u *Put) Start(ctx context.Context, wait chan struct{}) (Operations, error)
 
 			<-wait
 			for {
-				select {
-				case <-done:
+				intended, ok := u.nextStart(i, done)
+				if !ok {
 					return
-				default:
 				}
 				obj := src.Object()
+				u.prefixes.add(obj.Prefix)
 				opts.ContentType = obj.ContentType
 				client, cldone := u.Client()
 				op := Operation{
@@ -84,44 +77,29 @@ func (u *Put) Start(ctx context.Context, wait chan struct{}) (Operations, error)
 					File:     obj.Name,
 					ObjPerOp: 1,
 					Endpoint: client.EndpointURL().String(),
+					Intended: intended,
 				}
 				b, err := ioutil.ReadAll(obj.Reader)
+				if err != nil {
+					u.Error("generator error: ", err)
+					cldone()
+					continue
+				}
 				reader1 := bytes.NewReader(b)
 
-				myTerm, _ := context.WithTimeout(nonTerm, time.Duration((obj.Size/1024)+1)*time.Second)
+				myTerm, cancel := context.WithTimeout(nonTerm, time.Duration((obj.Size/1024)+1)*time.Second)
 
 				md5hash := md5.New()
 				md5hash.Write(b)
 				etag := fmt.Sprintf("%x", md5hash.Sum(nil))
 
 				op.Start = time.Now()
-
-				res, err := client.PutObject(myTerm, u.Bucket, obj.Name, reader1, obj.Size, opts)
-				//res, err := client.PutObject(nonTerm, u.Bucket, obj.Name, obj.Reader, obj.Size, opts)
+				res, err := client.PutObject(myTerm, u.bucketFor(obj.Name), obj.Name, reader1, obj.Size, opts)
 				op.End = time.Now()
-				writeLog := false
-				latency := op.End.Sub(op.Start).Seconds() * 1000
-
-				slow := op.End.Sub(op.Start).Seconds() < float64(obj.Size/1024/1024)
-
+				cancel()
 				if err != nil {
 					u.Error("upload error: ", err)
 					op.Err = err.Error()
-					writeLog = true
-					m := make(map[string]interface{})
-					m["status"] = "err"
-					m["action"] = "put"
-					m["bucket"] = u.Bucket
-					m["object"] = obj.Name
-					m["cost"] = latency
-					//m["etag"] = res.ETag
-					m["etag"] = etag
-					m["size"] = res.Size
-					m["msg"] = op.Err
-					m["start"] = op.Start.Format("2006-01-02T15:04:05")
-					m["slow"] = slow
-					m["endpoint"] = op.Endpoint
-					u.writeAccessLog(m)
 				}
 				obj.VersionID = res.VersionID
 				if res.Size != obj.Size && op.Err == "" {
@@ -132,41 +110,21 @@ func (u *Put) Start(ctx context.Context, wait chan struct{}) (Operations, error)
 					u.Error(err)
 				}
 
-				if !writeLog {
-					m := make(map[string]interface{})
-					m["status"] = "succ"
-					m["action"] = "put"
-					m["bucket"] = res.Bucket
-					m["object"] = res.Key
-					m["cost"] = latency
-					//m["etag"] = res.ETag
-					m["etag"] = etag
-					m["size"] = res.Size
-					m["msg"] = op.Err
-					m["start"] = op.Start.Format("2006-01-02T15:04:05")
-					m["slow"] = slow
-					m["endpoint"] = op.Endpoint
-					u.writeAccessLog(m)
-				}
-
 				op.Size = res.Size
+				rec := u.opRecord(op, obj.VersionID)
+				rec.ETag = etag
+				u.writeAccessLog(rec)
+
 				cldone()
 				rcv <- op
 			}
 		}(i)
 	}
 	wg.Wait()
-
-	u.closeAccessLog()
-
 	return c.Close(), nil
 }
 
 // Cleanup deletes everything uploaded to the bucket.
 func (u *Put) Cleanup(ctx context.Context) {
-	var pf []string
-	for p := range u.prefixes {
-		pf = append(pf, p)
-	}
-	u.deleteAllInBucket(ctx, pf...)
+	u.prefixes.deleteAll(ctx, &u.Common)
 }
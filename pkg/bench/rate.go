@@ -0,0 +1,126 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// RateLimiter schedules operation starts at a fixed rate,
+// independent of how fast previous operations complete.
+type RateLimiter struct {
+	mu       sync.Mutex
+	next     time.Time
+	interval time.Duration
+	rng      *rand.Rand
+}
+
+// NewRateLimiter returns a limiter scheduling rps operations per second.
+// If poisson is set, arrivals are spaced by exponentially distributed intervals
+// with the same mean.
+func NewRateLimiter(rps float64, poisson bool) *RateLimiter {
+	r := RateLimiter{interval: time.Duration(float64(time.Second) / rps)}
+	if poisson {
+		r.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return &r
+}
+
+// take returns the next scheduled start.
+// The first call starts the schedule.
+func (r *RateLimiter) take() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.next.IsZero() {
+		r.next = time.Now()
+	}
+	t := r.next
+	if r.rng != nil {
+		r.next = r.next.Add(time.Duration(r.rng.ExpFloat64() * float64(r.interval)))
+	} else {
+		r.next = r.next.Add(r.interval)
+	}
+	return t
+}
+
+// Ramp starts benchmark threads evenly spread over a duration.
+type Ramp struct {
+	dur  time.Duration
+	once sync.Once
+	from time.Time
+}
+
+// NewRamp returns a ramp starting all threads within d.
+func NewRamp(d time.Duration) *Ramp {
+	return &Ramp{dur: d}
+}
+
+// wait waits until thread n of threads should be started.
+// The ramp begins at the first call.
+// false is returned if done is closed.
+func (r *Ramp) wait(thread, threads int, done <-chan struct{}) bool {
+	r.once.Do(func() {
+		r.from = time.Now()
+	})
+	wait := time.Until(r.from.Add(r.dur * time.Duration(thread) / time.Duration(threads)))
+	if wait <= 0 {
+		return true
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// nextStart waits until the next operation of the thread should be started.
+// If no rate is set it returns immediately with a zero time.
+// When running at a fixed rate the scheduled start is returned,
+// even if the operation is started late.
+// false is returned if done is closed.
+func (c *Common) nextStart(thread int, done <-chan struct{}) (time.Time, bool) {
+	select {
+	case <-done:
+		return time.Time{}, false
+	default:
+	}
+	if c.Ramp != nil && !c.Ramp.wait(thread, c.Concurrency, done) {
+		return time.Time{}, false
+	}
+	if c.Rate == nil {
+		return time.Time{}, true
+	}
+	t := c.Rate.take()
+	wait := time.Until(t)
+	if wait <= 0 {
+		return t, true
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return time.Time{}, false
+	case <-timer.C:
+		return t, true
+	}
+}
@@ -0,0 +1,60 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	r := NewRateLimiter(100, false)
+	first := r.take()
+	for i := 1; i < 10; i++ {
+		if got, want := r.take().Sub(first), time.Duration(i)*10*time.Millisecond; got != want {
+			t.Fatalf("start %d: got offset %v, want %v", i, got, want)
+		}
+	}
+
+	r = NewRateLimiter(100, true)
+	first = r.take()
+	var last time.Time
+	const n = 10000
+	for i := 0; i < n; i++ {
+		last = r.take()
+	}
+	if mean := last.Sub(first) / n; mean < 9*time.Millisecond || mean > 11*time.Millisecond {
+		t.Fatalf("poisson mean interval %v, want about 10ms", mean)
+	}
+}
+
+func TestOperationIntended(t *testing.T) {
+	start := time.Now()
+	op := Operation{
+		Start: start,
+		End:   start.Add(time.Second),
+	}
+	if got := op.Duration(); got != time.Second {
+		t.Fatalf("got duration %v, want 1s", got)
+	}
+	// Delayed start is included in the latency.
+	op.Intended = start.Add(-time.Second)
+	if got := op.Duration(); got != 2*time.Second {
+		t.Fatalf("got duration %v, want 2s", got)
+	}
+}
@@ -0,0 +1,298 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio/pkg/console"
+	"github.com/minio/warp/pkg/accesslog"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// Replay re-executes requests recorded in an access log.
+type Replay struct {
+	// Requests to replay, sorted by time.
+	Requests accesslog.Entries
+	// Speed scales the time between requests.
+	// A value of 2 will replay the requests twice as fast as they were recorded.
+	Speed     float64
+	Collector *Collector
+
+	GetOpts  minio.GetObjectOptions
+	StatOpts minio.StatObjectOptions
+	Common
+
+	// multiBucket is set if the requests are to more than one bucket.
+	multiBucket bool
+}
+
+// Prepare will create an empty bucket or delete any content already there
+// and upload the objects that are read before they are written in the log.
+func (r *Replay) Prepare(ctx context.Context) error {
+	if len(r.Requests) == 0 {
+		return errors.New("no requests to replay")
+	}
+	if r.Speed <= 0 {
+		return errors.New("replay speed must be > 0")
+	}
+	if err := r.createEmptyBucket(ctx); err != nil {
+		return err
+	}
+	r.Collector = NewCollector()
+	r.multiBucket = false
+	for _, e := range r.Requests {
+		if e.Bucket != r.Requests[0].Bucket {
+			r.multiBucket = true
+			break
+		}
+	}
+
+	// Find objects that must exist before the replay starts.
+	written := make(map[string]struct{})
+	sizes := make(map[string]int64)
+	var keys []string
+	for _, e := range r.Requests {
+		key := r.objectName(e)
+		switch e.Method {
+		case http.MethodPut:
+			written[key] = struct{}{}
+		case http.MethodGet, http.MethodHead:
+			if _, ok := written[key]; ok || e.Err {
+				continue
+			}
+			sz, ok := sizes[key]
+			if !ok {
+				keys = append(keys, key)
+			}
+			if e.Size > sz {
+				sizes[key] = e.Size
+			}
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	console.Info("\rUploading ", len(keys), " objects read by the log")
+	work := make(chan string, len(keys))
+	for _, k := range keys {
+		work <- k
+	}
+	close(work)
+
+	var wg sync.WaitGroup
+	wg.Add(r.Concurrency)
+	var groupErr error
+	var mu sync.Mutex
+	uploaded := 0
+	for i := 0; i < r.Concurrency; i++ {
+		go func(i int) {
+			defer wg.Done()
+			src := r.Source()
+			opts := r.PutOpts
+			for key := range work {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+				}
+				obj := src.Object()
+				size := sizes[key]
+				client, cldone := r.Client()
+				op := Operation{
+					OpType:   http.MethodPut,
+					Thread:   uint16(i),
+					Size:     size,
+					File:     key,
+					ObjPerOp: 1,
+					Endpoint: client.EndpointURL().String(),
+				}
+				opts.ContentType = obj.ContentType
+				op.Start = time.Now()
+				res, err := client.PutObject(ctx, r.bucketFor(key), key, io.LimitReader(obj.Reader, size), size, opts)
+				op.End = time.Now()
+				cldone()
+				if err != nil {
+					err := fmt.Errorf("upload error: %w", err)
+					r.Error(err)
+					mu.Lock()
+					if groupErr == nil {
+						groupErr = err
+					}
+					mu.Unlock()
+					op.Err = err.Error()
+					r.logOp(op, "")
+					return
+				}
+				r.logOp(op, res.VersionID)
+				mu.Lock()
+				uploaded++
+				r.prepareProgress(float64(uploaded) / float64(len(keys)))
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	return groupErr
+}
+
+// Start will replay the requests with the recorded timing.
+// Requests are delayed if all concurrent operations are busy.
+// Operations should begin executing when the start channel is closed.
+func (r *Replay) Start(ctx context.Context, wait chan struct{}) (Operations, error) {
+	var wg sync.WaitGroup
+	wg.Add(r.Concurrency)
+	c := r.Collector.reopen()
+	reqs := make(chan replayRequest)
+
+	// Non-terminating context.
+	nonTerm := context.Background()
+
+	for i := 0; i < r.Concurrency; i++ {
+		go func(i int) {
+			rcv := c.Receiver()
+			defer wg.Done()
+			src := r.Source()
+			for req := range reqs {
+				op := r.replay(nonTerm, uint16(i), src, req.Entry)
+				// Latency includes time waiting for a free thread.
+				op.Intended = req.due
+				r.logOp(op, "")
+				rcv <- op
+			}
+		}(i)
+	}
+
+	done := ctx.Done()
+	timer := time.NewTimer(0)
+	<-timer.C
+	<-wait
+	start := time.Now()
+	first := r.Requests[0].Time
+dispatch:
+	for _, e := range r.Requests {
+		var due time.Time
+		if !e.Time.IsZero() && !first.IsZero() {
+			due = start.Add(time.Duration(float64(e.Time.Sub(first)) / r.Speed))
+			if d := time.Until(due); d > 0 {
+				timer.Reset(d)
+				select {
+				case <-done:
+					timer.Stop()
+					break dispatch
+				case <-timer.C:
+				}
+			}
+		}
+		select {
+		case <-done:
+			break dispatch
+		case reqs <- replayRequest{Entry: e, due: due}:
+		}
+	}
+	close(reqs)
+	wg.Wait()
+	return c.Close(), nil
+}
+
+// replayRequest is a request to replay and the time it is due.
+type replayRequest struct {
+	accesslog.Entry
+	due time.Time
+}
+
+// replay executes a single request.
+// Data for uploads is taken from src, which must produce objects
+// at least as big as the biggest upload.
+func (r *Replay) replay(ctx context.Context, thread uint16, src generator.Source, e accesslog.Entry) Operation {
+	client, cldone := r.Client()
+	defer cldone()
+	key := r.objectName(e)
+	op := Operation{
+		OpType:   e.Method,
+		Thread:   thread,
+		Size:     e.Size,
+		File:     key,
+		ObjPerOp: 1,
+		Endpoint: client.EndpointURL().String(),
+	}
+	op.Start = time.Now()
+	var err error
+	switch e.Method {
+	case http.MethodPut:
+		obj := src.Object()
+		opts := r.PutOpts
+		opts.ContentType = obj.ContentType
+		var res minio.UploadInfo
+		res, err = client.PutObject(ctx, r.bucketFor(key), key, io.LimitReader(obj.Reader, e.Size), e.Size, opts)
+		if err == nil && res.Size != e.Size {
+			err = fmt.Errorf("short upload. want: %d, got %d", e.Size, res.Size)
+		}
+	case http.MethodGet:
+		fbr := firstByteRecorder{}
+		var o *minio.Object
+		o, err = client.GetObject(ctx, r.bucketFor(key), key, r.GetOpts)
+		if err == nil {
+			fbr.r = o
+			op.Size, err = io.Copy(ioutil.Discard, &fbr)
+			op.FirstByte = fbr.t
+			o.Close()
+		}
+	case http.MethodHead:
+		op.OpType = "STAT"
+		op.Size = 0
+		_, err = client.StatObject(ctx, r.bucketFor(key), key, r.StatOpts)
+	case http.MethodDelete:
+		op.Size = 0
+		err = client.RemoveObject(ctx, r.bucketFor(key), key, minio.RemoveObjectOptions{})
+	default:
+		err = fmt.Errorf("unsupported method %q", e.Method)
+	}
+	op.End = time.Now()
+	if err != nil {
+		op.Err = err.Error()
+		// Errors are expected if the request failed when it was recorded.
+		if !e.Err {
+			r.Error(e.Method, " ", key, ": ", err)
+		}
+	}
+	return op
+}
+
+// objectName returns the name a request is replayed to.
+// If the log contains requests to several buckets the objects are
+// prefixed by the bucket name, so the buckets are kept apart.
+func (r *Replay) objectName(e accesslog.Entry) string {
+	if r.multiBucket {
+		return e.Bucket + "/" + e.Key
+	}
+	return e.Key
+}
+
+// Cleanup deletes everything uploaded to the bucket.
+func (r *Replay) Cleanup(ctx context.Context) {
+	r.deleteAllInBucket(ctx)
+}
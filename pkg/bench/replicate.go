@@ -0,0 +1,307 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio/pkg/console"
+)
+
+const (
+	// OpReplicatePut is recorded when an upload has been observed on the remote.
+	// It lasts from the end of the upload until it was observed.
+	OpReplicatePut = "REPLICATE_PUT"
+	// OpReplicateDelete is recorded when a delete has been observed on the remote.
+	// It lasts from the end of the delete until it was observed.
+	OpReplicateDelete = "REPLICATE_DELETE"
+)
+
+// Replicate measures the replication lag between two sets of endpoints.
+// Objects are written through Client and polled on RemoteClient until they are visible.
+type Replicate struct {
+	// RemoteClient is used for polling the replication target.
+	RemoteClient func() (cl *minio.Client, done func())
+	// RemoteBucket is the bucket polled on the remote.
+	// The local bucket name is used if empty.
+	RemoteBucket string
+	// DeleteRatio is the fraction of writes that delete a replicated object.
+	DeleteRatio float64
+	// Poll is the time between polls of pending objects.
+	Poll time.Duration
+	// Timeout is the maximum time to wait for a write to be replicated.
+	Timeout time.Duration
+
+	Collector *Collector
+	StatOpts  minio.StatObjectOptions
+	Common
+
+	prefixes uploadedPrefixes
+}
+
+// replicatePollers is the maximum number of writes each poller checks concurrently.
+const replicatePollers = 16
+
+// replicateWrite is a write waiting to be observed on the remote.
+type replicateWrite struct {
+	name    string
+	size    int64
+	deleted bool
+	written time.Time
+}
+
+// Prepare will create an empty local bucket or delete any content already there
+// and check that the remote bucket exists.
+func (r *Replicate) Prepare(ctx context.Context) error {
+	r.Collector = NewCollector()
+	if err := r.createEmptyBucket(ctx); err != nil {
+		return err
+	}
+	client, done := r.RemoteClient()
+	defer done()
+	for _, bucket := range r.buckets() {
+		bucket = r.remoteBucket(bucket)
+		ok, err := client.BucketExists(ctx, bucket)
+		if err != nil {
+			return fmt.Errorf("remote bucket: %w", err)
+		}
+		if !ok {
+			return fmt.Errorf("remote bucket %q does not exist", bucket)
+		}
+	}
+	console.Info("\rPolling replication on ", client.EndpointURL().Host)
+	return nil
+}
+
+// remoteBucket returns the remote bucket of a local bucket.
+func (r *Replicate) remoteBucket(local string) string {
+	if r.RemoteBucket != "" {
+		return r.RemoteBucket
+	}
+	return local
+}
+
+// Start will execute the main benchmark.
+// Each thread writes objects and has a poller waiting for its writes to be replicated.
+// Writes still pending when the benchmark ends are polled until they are observed or time out.
+// Operations should begin executing when the start channel is closed.
+func (r *Replicate) Start(ctx context.Context, wait chan struct{}) (Operations, error) {
+	var wg sync.WaitGroup
+	wg.Add(r.Concurrency * 2)
+	c := r.Collector.reopen()
+	if r.AutoTermDur > 0 {
+		ctx = c.AutoTerm(ctx, http.MethodPut, r.AutoTermScale, autoTermCheck, autoTermSamples, r.AutoTermDur)
+	}
+
+	// Non-terminating context.
+	nonTerm := context.Background()
+	var behindOnce sync.Once
+
+	for i := 0; i < r.Concurrency; i++ {
+		pending := make(chan replicateWrite, 1000)
+		// Replicated objects that can be deleted.
+		var mu sync.Mutex
+		var replicated []replicateWrite
+
+		go func(i int) {
+			rng := rand.New(rand.NewSource(int64(i)))
+			rcv := c.Receiver()
+			defer wg.Done()
+			defer close(pending)
+			opts := r.PutOpts
+			done := ctx.Done()
+			src := r.Source()
+
+			<-wait
+			for {
+				intended, ok := r.nextStart(i, done)
+				if !ok {
+					return
+				}
+				var del *replicateWrite
+				if r.DeleteRatio > 0 && rng.Float64() < r.DeleteRatio {
+					mu.Lock()
+					if n := len(replicated); n > 0 {
+						idx := rng.Intn(n)
+						w := replicated[idx]
+						replicated[idx] = replicated[n-1]
+						replicated = replicated[:n-1]
+						del = &w
+					}
+					mu.Unlock()
+				}
+				client, cldone := r.Client()
+				op := Operation{
+					OpType:   http.MethodPut,
+					Thread:   uint16(i),
+					ObjPerOp: 1,
+					Endpoint: client.EndpointURL().String(),
+					Intended: intended,
+				}
+				var err error
+				var versionID string
+				if del != nil {
+					op.OpType = http.MethodDelete
+					op.File = del.name
+					op.Start = time.Now()
+					err = client.RemoveObject(nonTerm, r.bucketFor(del.name), del.name, minio.RemoveObjectOptions{})
+					op.End = time.Now()
+					if err != nil {
+						r.Error("delete error: ", err)
+					}
+				} else {
+					obj := src.Object()
+					r.prefixes.add(obj.Prefix)
+					op.File = obj.Name
+					op.Size = obj.Size
+					opts.ContentType = obj.ContentType
+					op.Start = time.Now()
+					var res minio.UploadInfo
+					res, err = client.PutObject(nonTerm, r.bucketFor(obj.Name), obj.Name, obj.Reader, obj.Size, opts)
+					op.End = time.Now()
+					if err == nil && res.Size != obj.Size {
+						err = fmt.Errorf("short upload. want: %d, got %d", obj.Size, res.Size)
+					}
+					if err != nil {
+						r.Error("upload error: ", err)
+					}
+					versionID = res.VersionID
+				}
+				cldone()
+				if err != nil {
+					op.Err = err.Error()
+				} else {
+					pending <- replicateWrite{name: op.File, size: op.Size, deleted: del != nil, written: op.End}
+				}
+				r.logOp(op, versionID)
+				rcv <- op
+			}
+		}(i)
+
+		go func(i int) {
+			rcv := c.Receiver()
+			defer wg.Done()
+			ticker := time.NewTicker(r.Poll)
+			defer ticker.Stop()
+			var queue []replicateWrite
+			in := pending
+			for in != nil || len(queue) > 0 {
+				select {
+				case w, ok := <-in:
+					if !ok {
+						in = nil
+						continue
+					}
+					queue = append(queue, w)
+					continue
+				case <-ticker.C:
+				}
+				started := time.Now()
+				client, cldone := r.RemoteClient()
+				observed := make([]bool, len(queue))
+				var pollWg sync.WaitGroup
+				sem := make(chan struct{}, replicatePollers)
+				for idx, w := range queue {
+					sem <- struct{}{}
+					pollWg.Add(1)
+					go func(idx int, w replicateWrite) {
+						defer func() {
+							<-sem
+							pollWg.Done()
+						}()
+						op, ok := r.poll(nonTerm, client, w)
+						if !ok {
+							return
+						}
+						observed[idx] = true
+						op.Thread = uint16(i)
+						rcv <- op
+						if op.Err == "" && !w.deleted {
+							mu.Lock()
+							replicated = append(replicated, w)
+							mu.Unlock()
+						}
+					}(idx, w)
+				}
+				pollWg.Wait()
+				cldone()
+				remain := queue[:0]
+				for idx, w := range queue {
+					if !observed[idx] {
+						remain = append(remain, w)
+					}
+				}
+				queue = remain
+				// Report once when polling all pending writes takes longer than the poll interval.
+				if took := time.Since(started); took > r.Poll {
+					behindOnce.Do(func() {
+						r.Error(fmt.Sprintf("replication polling is falling behind, polling %d writes took %v. Measured delays may be too high.", len(observed), took.Round(time.Millisecond)))
+					})
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	return c.Close(), nil
+}
+
+// poll checks if a write has been replicated.
+// If it has, or the timeout has been reached, the replication operation is returned.
+func (r *Replicate) poll(ctx context.Context, client *minio.Client, w replicateWrite) (Operation, bool) {
+	op := Operation{
+		OpType:   OpReplicatePut,
+		File:     w.name,
+		Size:     w.size,
+		ObjPerOp: 1,
+		Endpoint: client.EndpointURL().String(),
+		Start:    w.written,
+	}
+	if w.deleted {
+		op.OpType = OpReplicateDelete
+	}
+	info, err := client.StatObject(ctx, r.remoteBucket(r.bucketFor(w.name)), w.name, r.StatOpts)
+	op.End = time.Now()
+	var visible bool
+	switch {
+	case err == nil:
+		visible = !w.deleted && info.Size == w.size
+	case minio.ToErrorResponse(err).Code == "NoSuchKey":
+		visible = w.deleted
+	default:
+		r.Error("remote stat error: ", err)
+	}
+	if visible {
+		return op, true
+	}
+	if op.End.Sub(w.written) >= r.Timeout {
+		op.Err = fmt.Sprintf("%s: not replicated after %v", w.name, r.Timeout)
+		return op, true
+	}
+	return op, false
+}
+
+// Cleanup deletes everything uploaded to the local bucket.
+func (r *Replicate) Cleanup(ctx context.Context) {
+	r.prefixes.deleteAll(ctx, &r.Common)
+}
@@ -87,7 +87,7 @@ func (g *Select) Prepare(ctx context.Context) error {
 				}
 				opts.ContentType = obj.ContentType
 				op.Start = time.Now()
-				res, err := client.PutObject(ctx, g.Bucket, obj.Name, obj.Reader, obj.Size, opts)
+				res, err := client.PutObject(ctx, g.bucketFor(obj.Name), obj.Name, obj.Reader, obj.Size, opts)
 				op.End = time.Now()
 				if err != nil {
 					err := fmt.Errorf("upload error: %w", err)
@@ -97,6 +97,8 @@ func (g *Select) Prepare(ctx context.Context) error {
 						groupErr = err
 					}
 					mu.Unlock()
+					op.Err = err.Error()
+					g.logOp(op, "")
 					return
 				}
 				obj.VersionID = res.VersionID
@@ -108,8 +110,11 @@ func (g *Select) Prepare(ctx context.Context) error {
 						groupErr = err
 					}
 					mu.Unlock()
+					op.Err = err.Error()
+					g.logOp(op, obj.VersionID)
 					return
 				}
+				g.logOp(op, obj.VersionID)
 				cldone()
 				mu.Lock()
 				obj.Reader = nil
@@ -129,7 +134,7 @@ func (g *Select) Prepare(ctx context.Context) error {
 func (g *Select) Start(ctx context.Context, wait chan struct{}) (Operations, error) {
 	var wg sync.WaitGroup
 	wg.Add(g.Concurrency)
-	c := g.Collector
+	c := g.Collector.reopen()
 	if g.AutoTermDur > 0 {
 		ctx = c.AutoTerm(ctx, "SELECT", g.AutoTermScale, autoTermCheck, autoTermSamples, g.AutoTermDur)
 	}
@@ -147,13 +152,12 @@ func (g *Select) Start(ctx context.Context, wait chan struct{}) (Operations, err
 
 			<-wait
 			for {
-				select {
-				case <-done:
+				intended, ok := g.nextStart(i, done)
+				if !ok {
 					return
-				default:
 				}
 				fbr := firstByteRecorder{}
-				obj := g.objects[rng.Intn(len(g.objects))]
+				obj := g.objects[g.objectIndex(rng, len(g.objects))]
 				client, cldone := g.Client()
 				op := Operation{
 					OpType:   "SELECT",
@@ -162,15 +166,17 @@ func (g *Select) Start(ctx context.Context, wait chan struct{}) (Operations, err
 					File:     obj.Name,
 					ObjPerOp: 1,
 					Endpoint: client.EndpointURL().String(),
+					Intended: intended,
 				}
 				op.Start = time.Now()
 				var err error
-				o, err := client.SelectObjectContent(nonTerm, g.Bucket, obj.Name, opts)
+				o, err := client.SelectObjectContent(nonTerm, g.bucketFor(obj.Name), obj.Name, opts)
 				fbr.r = o
 				if err != nil {
 					g.Error("download error: ", err)
 					op.Err = err.Error()
 					op.End = time.Now()
+					g.logOp(op, obj.VersionID)
 					rcv <- op
 					cldone()
 					continue
@@ -182,6 +188,7 @@ func (g *Select) Start(ctx context.Context, wait chan struct{}) (Operations, err
 				}
 				op.FirstByte = fbr.t
 				op.End = time.Now()
+				g.logOp(op, obj.VersionID)
 				rcv <- op
 				cldone()
 				o.Close()
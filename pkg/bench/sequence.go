@@ -0,0 +1,232 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// OpSequence is the operation type recorded for a whole sequence.
+const OpSequence = "SEQUENCE"
+
+// Sequence is the lifecycle of an object,
+// run by each thread of the mixed benchmark instead of random operations.
+type Sequence struct {
+	// Steps contains the operation of each step.
+	Steps []string
+	// Think is the time to wait between steps.
+	Think time.Duration
+	// ThinkMax will make the wait random between Think and ThinkMax if bigger than Think.
+	ThinkMax time.Duration
+}
+
+// ParseSequence parses a comma separated list of steps, for example "PUT,GET*3,STAT,DELETE".
+// Steps can be PUT, GET, STAT and DELETE and can be repeated by adding "*n".
+// The first step must be PUT, and DELETE can only be the last step.
+func ParseSequence(s string) (*Sequence, error) {
+	var seq Sequence
+	for _, step := range strings.Split(s, ",") {
+		step = strings.ToUpper(strings.TrimSpace(step))
+		n := 1
+		if idx := strings.IndexByte(step, '*'); idx >= 0 {
+			var err error
+			n, err = strconv.Atoi(step[idx+1:])
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid repeat count in step %q", step)
+			}
+			step = step[:idx]
+		}
+		switch step {
+		case http.MethodPut, http.MethodGet, "STAT", http.MethodDelete:
+		case http.MethodHead:
+			step = "STAT"
+		default:
+			return nil, fmt.Errorf("unknown sequence step %q", step)
+		}
+		for i := 0; i < n; i++ {
+			seq.Steps = append(seq.Steps, step)
+		}
+	}
+	if seq.Steps[0] != http.MethodPut {
+		return nil, errors.New("sequence must start with PUT")
+	}
+	for _, step := range seq.Steps[:len(seq.Steps)-1] {
+		if step == http.MethodDelete {
+			return nil, errors.New("DELETE can only be the last step of a sequence")
+		}
+	}
+	return &seq, nil
+}
+
+// stepName returns the operation type step i is reported as.
+// Steps with an operation used more than once in the sequence are numbered by position,
+// so each of them is analyzed separately.
+func (s *Sequence) stepName(i int) string {
+	step := s.Steps[i]
+	for j, other := range s.Steps {
+		if j != i && other == step {
+			return fmt.Sprintf("%s#%d", step, i+1)
+		}
+	}
+	return step
+}
+
+// think waits between steps.
+// false is returned if done is closed.
+func (s *Sequence) think(rng *rand.Rand, done <-chan struct{}) bool {
+	wait := s.Think
+	if s.ThinkMax > wait {
+		wait += time.Duration(rng.Int63n(int64(s.ThinkMax - wait)))
+	}
+	if wait <= 0 {
+		return true
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// runSequence runs the sequence on a new object from src.
+// Every step is sent to rcv, followed by an operation covering the whole sequence.
+// The sequence is stopped if a step fails or done is closed while waiting between steps.
+func (g *Mixed) runSequence(ctx context.Context, thread uint16, src generator.Source, rng *rand.Rand, intended time.Time, done <-chan struct{}, rcv chan<- Operation) {
+	obj := src.Object()
+	sums, err := g.verifySums(obj)
+	if err != nil {
+		g.Error(err)
+		return
+	}
+	seq := Operation{
+		OpType:   OpSequence,
+		Thread:   thread,
+		File:     obj.Name,
+		ObjPerOp: 1,
+		Intended: intended,
+	}
+	var exists, stopped bool
+	for i, step := range g.Sequence.Steps {
+		if i > 0 && !g.Sequence.think(rng, done) {
+			stopped = true
+			break
+		}
+		client, clDone := g.Client()
+		op := Operation{
+			OpType:   step,
+			Thread:   thread,
+			File:     obj.Name,
+			ObjPerOp: 1,
+			Endpoint: client.EndpointURL().String(),
+		}
+		if i == 0 {
+			op.Intended = intended
+		}
+		op.Start = time.Now()
+		if i == 0 {
+			seq.Start = op.Start
+			seq.Endpoint = op.Endpoint
+		}
+		switch step {
+		case http.MethodPut:
+			op.Size = obj.Size
+			opts := g.PutOpts
+			opts.ContentType = obj.ContentType
+			if _, err = obj.Reader.Seek(0, io.SeekStart); err != nil {
+				break
+			}
+			var res minio.UploadInfo
+			res, err = client.PutObject(ctx, g.bucketFor(obj.Name), obj.Name, obj.Reader, obj.Size, opts)
+			if err == nil && res.Size != obj.Size {
+				err = fmt.Errorf("short upload. want: %d, got %d", obj.Size, res.Size)
+			}
+			if err == nil {
+				obj.VersionID = res.VersionID
+				exists = true
+				g.verifyAdd(*obj, sums)
+			}
+		case http.MethodGet:
+			op.Size = obj.Size
+			opts := g.GetOpts
+			opts.VersionID = obj.VersionID
+			var o *minio.Object
+			o, err = client.GetObject(ctx, g.bucketFor(obj.Name), obj.Name, opts)
+			if err != nil {
+				break
+			}
+			fbr := firstByteRecorder{r: o}
+			var n int64
+			n, err = io.Copy(ioutil.Discard, g.verifyReader(*obj, &fbr, 0))
+			op.FirstByte = fbr.t
+			o.Close()
+			if err == nil && n != obj.Size {
+				err = fmt.Errorf("unexpected download size. want: %d, got %d", obj.Size, n)
+			}
+		case "STAT":
+			var info minio.ObjectInfo
+			info, err = client.StatObject(ctx, g.bucketFor(obj.Name), obj.Name, g.StatOpts)
+			if err == nil && info.Size != obj.Size {
+				err = fmt.Errorf("unexpected stat size. want: %d, got %d", obj.Size, info.Size)
+			}
+		case http.MethodDelete:
+			err = client.RemoveObject(ctx, g.bucketFor(obj.Name), obj.Name, minio.RemoveObjectOptions{VersionID: obj.VersionID})
+			if err == nil {
+				exists = false
+				g.verifyRemove(*obj)
+			}
+		}
+		op.End = time.Now()
+		clDone()
+		seq.End = op.End
+		if err != nil {
+			g.Error(strings.ToLower(step), " error: ", err)
+			op.Err = err.Error()
+			op.ErrKind = errKind(err)
+			seq.Err = fmt.Sprintf("step %d (%s) failed: %v", i+1, step, err)
+		}
+		g.logOp(op, obj.VersionID)
+		op.OpType = g.Sequence.stepName(i)
+		rcv <- op
+		if err != nil {
+			break
+		}
+	}
+	if exists {
+		// Keep track of the object, so it is removed by cleanup.
+		obj.Reader = nil
+		g.Dist.addObj(*obj)
+	}
+	if !stopped {
+		rcv <- seq
+	}
+}
@@ -0,0 +1,72 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSequence(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{in: "PUT", want: []string{"PUT"}},
+		{in: "put, get*2, head, delete", want: []string{"PUT", "GET", "GET", "STAT", "DELETE"}},
+		{in: "PUT,STAT*2", want: []string{"PUT", "STAT", "STAT"}},
+		{in: "GET,PUT", wantErr: true},
+		{in: "PUT,DELETE,GET", wantErr: true},
+		{in: "PUT,GET*0", wantErr: true},
+		{in: "PUT,LIST", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			seq, err := ParseSequence(test.in)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("want error, got %v", seq.Steps)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(seq.Steps, test.want) {
+				t.Errorf("want %v, got %v", test.want, seq.Steps)
+			}
+		})
+	}
+}
+
+func TestSequenceStepName(t *testing.T) {
+	seq, err := ParseSequence("PUT,GET*3,STAT,DELETE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"PUT", "GET#2", "GET#3", "GET#4", "STAT", "DELETE"}
+	var got []string
+	for i := range seq.Steps {
+		got = append(got, seq.stepName(i))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
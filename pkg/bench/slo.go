@@ -0,0 +1,148 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+// SLOVerdict is the result of checking an operation against its latency objective.
+type SLOVerdict string
+
+const (
+	// SLONone is used when the operation has no objective.
+	SLONone SLOVerdict = ""
+	// SLOMet is used when the operation met its objective.
+	SLOMet SLOVerdict = "ok"
+	// SLOViolated is used when the operation was too slow or failed.
+	SLOViolated SLOVerdict = "violated"
+)
+
+// SLO is a latency objective for an operation type.
+// An operation violates the objective if it takes longer than
+// Max plus PerMiB for each MiB transferred.
+type SLO struct {
+	Max    time.Duration
+	PerMiB time.Duration
+}
+
+// Limit returns the longest allowed duration of an operation transferring size bytes.
+func (s SLO) Limit(size int64) time.Duration {
+	return s.Max + time.Duration(float64(s.PerMiB)*float64(size)/(1<<20))
+}
+
+// String returns the objective in the format accepted by ParseSLOs.
+func (s SLO) String() string {
+	switch {
+	case s.PerMiB == 0:
+		return s.Max.String()
+	case s.Max == 0:
+		return s.PerMiB.String() + "/MiB"
+	}
+	return s.Max.String() + "+" + s.PerMiB.String() + "/MiB"
+}
+
+// SLOs contains latency objectives by operation type.
+// The objective of "*" is used for operation types without an objective.
+type SLOs map[string]SLO
+
+// ParseSLOs parses a comma separated list of objectives.
+// Each objective is formatted as "OP:limit", where OP is an operation type or "*".
+// The limit can be a duration ("100ms"), a duration per MiB ("20ms/MiB"),
+// both ("50ms+20ms/MiB") or a minimum throughput ("10MiB/s").
+func ParseSLOs(s string) (SLOs, error) {
+	res := make(SLOs)
+	for _, def := range strings.Split(s, ",") {
+		def = strings.TrimSpace(def)
+		if def == "" {
+			continue
+		}
+		kv := strings.SplitN(def, ":", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("slo: want OP:limit, got %q", def)
+		}
+		slo, err := parseSLO(kv[1])
+		if err != nil {
+			return nil, fmt.Errorf("slo %q: %w", def, err)
+		}
+		res[strings.ToUpper(kv[0])] = slo
+	}
+	return res, nil
+}
+
+func parseSLO(s string) (SLO, error) {
+	var slo SLO
+	// Minimum throughput.
+	if strings.HasSuffix(s, "/s") {
+		bps, err := humanize.ParseBytes(strings.TrimSuffix(s, "/s"))
+		if err != nil {
+			return slo, err
+		}
+		if bps == 0 {
+			return slo, fmt.Errorf("throughput must be > 0")
+		}
+		slo.PerMiB = time.Duration(float64(time.Second) * (1 << 20) / float64(bps))
+		return slo, nil
+	}
+	for _, part := range strings.Split(s, "+") {
+		perMiB := strings.HasSuffix(part, "/MiB")
+		d, err := time.ParseDuration(strings.TrimSuffix(part, "/MiB"))
+		if err != nil {
+			return slo, err
+		}
+		if d <= 0 {
+			return slo, fmt.Errorf("limit must be > 0")
+		}
+		if perMiB {
+			slo.PerMiB += d
+		} else {
+			slo.Max += d
+		}
+	}
+	return slo, nil
+}
+
+// Verdict returns the verdict of an operation.
+// Failed operations always violate their objective.
+func (s SLOs) Verdict(op Operation) SLOVerdict {
+	slo, ok := s[op.OpType]
+	if !ok {
+		if slo, ok = s["*"]; !ok {
+			return SLONone
+		}
+	}
+	if op.Err != "" || op.Duration() > slo.Limit(op.Size) {
+		return SLOViolated
+	}
+	return SLOMet
+}
+
+// CheckSLOs sets the SLO verdict of all operations.
+// If no objectives are given existing verdicts are kept.
+func (o Operations) CheckSLOs(s SLOs) {
+	if len(s) == 0 {
+		return
+	}
+	for i := range o {
+		o[i].SLO = s.Verdict(o[i])
+	}
+}
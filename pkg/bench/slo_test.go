@@ -0,0 +1,60 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSLOs(t *testing.T) {
+	slos, err := ParseSLOs("GET:100ms, put:50ms+20ms/MiB,STAT:10MiB/s,*:1s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	tests := []struct {
+		op   Operation
+		want SLOVerdict
+	}{
+		{op: Operation{OpType: "GET", End: start.Add(99 * time.Millisecond)}, want: SLOMet},
+		{op: Operation{OpType: "GET", End: start.Add(101 * time.Millisecond)}, want: SLOViolated},
+		{op: Operation{OpType: "GET", End: start.Add(time.Millisecond), Err: "failed"}, want: SLOViolated},
+		// 50ms + 10 * 20ms
+		{op: Operation{OpType: "PUT", Size: 10 << 20, End: start.Add(249 * time.Millisecond)}, want: SLOMet},
+		{op: Operation{OpType: "PUT", Size: 10 << 20, End: start.Add(251 * time.Millisecond)}, want: SLOViolated},
+		// 100ms per MiB
+		{op: Operation{OpType: "STAT", Size: 5 << 20, End: start.Add(499 * time.Millisecond)}, want: SLOMet},
+		{op: Operation{OpType: "STAT", Size: 5 << 20, End: start.Add(501 * time.Millisecond)}, want: SLOViolated},
+		{op: Operation{OpType: "DELETE", End: start.Add(2 * time.Second)}, want: SLOViolated},
+	}
+	for i, test := range tests {
+		test.op.Start = start
+		if got := slos.Verdict(test.op); got != test.want {
+			t.Errorf("test %d: want %q, got %q", i, test.want, got)
+		}
+	}
+	if got := (SLOs{"GET": {}}).Verdict(Operation{OpType: "PUT"}); got != SLONone {
+		t.Errorf("want no verdict, got %q", got)
+	}
+	for _, invalid := range []string{"GET", "GET:fast", "GET:0s", "PUT:0MiB/s"} {
+		if _, err := ParseSLOs(invalid); err == nil {
+			t.Errorf("%q: want error", invalid)
+		}
+	}
+}
@@ -85,7 +85,7 @@ func (g *Stat) Prepare(ctx context.Context) error {
 				}
 				opts.ContentType = obj.ContentType
 				op.Start = time.Now()
-				res, err := client.PutObject(ctx, g.Bucket, obj.Name, obj.Reader, obj.Size, opts)
+				res, err := client.PutObject(ctx, g.bucketFor(obj.Name), obj.Name, obj.Reader, obj.Size, opts)
 				op.End = time.Now()
 				if err != nil {
 					err := fmt.Errorf("upload error: %w", err)
@@ -95,6 +95,8 @@ func (g *Stat) Prepare(ctx context.Context) error {
 						groupErr = err
 					}
 					mu.Unlock()
+					op.Err = err.Error()
+					g.logOp(op, "")
 					return
 				}
 
@@ -107,8 +109,11 @@ func (g *Stat) Prepare(ctx context.Context) error {
 						groupErr = err
 					}
 					mu.Unlock()
+					op.Err = err.Error()
+					g.logOp(op, obj.VersionID)
 					return
 				}
+				g.logOp(op, obj.VersionID)
 				cldone()
 				mu.Lock()
 				obj.Reader = nil
@@ -128,7 +133,7 @@ func (g *Stat) Prepare(ctx context.Context) error {
 func (g *Stat) Start(ctx context.Context, wait chan struct{}) (Operations, error) {
 	var wg sync.WaitGroup
 	wg.Add(g.Concurrency)
-	c := g.Collector
+	c := g.Collector.reopen()
 	if g.AutoTermDur > 0 {
 		ctx = c.AutoTerm(ctx, "STAT", g.AutoTermScale, autoTermCheck, autoTermSamples, g.AutoTermDur)
 	}
@@ -145,12 +150,11 @@ func (g *Stat) Start(ctx context.Context, wait chan struct{}) (Operations, error
 
 			<-wait
 			for {
-				select {
-				case <-done:
+				intended, ok := g.nextStart(i, done)
+				if !ok {
 					return
-				default:
 				}
-				obj := g.objects[rng.Intn(len(g.objects))]
+				obj := g.objects[g.objectIndex(rng, len(g.objects))]
 				client, cldone := g.Client()
 				op := Operation{
 					OpType:   "STAT",
@@ -159,15 +163,17 @@ func (g *Stat) Start(ctx context.Context, wait chan struct{}) (Operations, error
 					File:     obj.Name,
 					ObjPerOp: 1,
 					Endpoint: client.EndpointURL().String(),
+					Intended: intended,
 				}
 				op.Start = time.Now()
 				var err error
 				opts.VersionID = obj.VersionID
-				objI, err := client.StatObject(nonTerm, g.Bucket, obj.Name, opts)
+				objI, err := client.StatObject(nonTerm, g.bucketFor(obj.Name), obj.Name, opts)
 				if err != nil {
 					g.Error("StatObject error: ", err)
 					op.Err = err.Error()
 					op.End = time.Now()
+					g.logOp(op, obj.VersionID)
 					rcv <- op
 					cldone()
 					continue
@@ -177,6 +183,7 @@ func (g *Stat) Start(ctx context.Context, wait chan struct{}) (Operations, error
 					op.Err = fmt.Sprint("unexpected file size. want:", obj.Size, ", got:", objI.Size)
 					g.Error(op.Err)
 				}
+				g.logOp(op, obj.VersionID)
 				rcv <- op
 				cldone()
 			}
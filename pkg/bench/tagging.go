@@ -0,0 +1,154 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/tags"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// Tagging benchmarks setting and getting object tags.
+type Tagging struct {
+	CreateObjects int
+	Collector     *Collector
+	objects       generator.Objects
+
+	// Tags is the number of tags set on each object.
+	Tags int
+	// TagSize is the size of each tag value.
+	TagSize int
+	Common
+}
+
+// RandomTags returns n keys with random values of the specified size.
+// Keys are named prefix-0, prefix-1, etc.
+func RandomTags(rng *rand.Rand, prefix string, n, size int) map[string]string {
+	const letters = "abcdefghijklmnopqrstuvwxyz0123456789"
+	m := make(map[string]string, n)
+	b := make([]byte, size)
+	for i := 0; i < n; i++ {
+		for j := range b {
+			b[j] = letters[rng.Intn(len(letters))]
+		}
+		m[fmt.Sprintf("%s-%d", prefix, i)] = string(b)
+	}
+	return m
+}
+
+// Prepare will create an empty bucket or delete any content already there
+// and upload a number of objects.
+func (g *Tagging) Prepare(ctx context.Context) error {
+	if _, err := tags.MapToObjectTags(RandomTags(rand.New(rand.NewSource(0)), "warp", g.Tags, g.TagSize)); err != nil {
+		return err
+	}
+	if err := g.createEmptyBucket(ctx); err != nil {
+		return err
+	}
+	g.Collector = NewCollector()
+	objs, err := g.uploadObjects(ctx, g.CreateObjects, g.Collector, nil)
+	g.objects = objs
+	return err
+}
+
+// Start will execute the main benchmark.
+// Each thread sets new tags on a random object and reads them back.
+// Operations should begin executing when the start channel is closed.
+func (g *Tagging) Start(ctx context.Context, wait chan struct{}) (Operations, error) {
+	var wg sync.WaitGroup
+	wg.Add(g.Concurrency)
+	c := g.Collector.reopen()
+	if g.AutoTermDur > 0 {
+		ctx = c.AutoTerm(ctx, "PUT_TAGGING", g.AutoTermScale, autoTermCheck, autoTermSamples, g.AutoTermDur)
+	}
+	// Non-terminating context.
+	nonTerm := context.Background()
+
+	for i := 0; i < g.Concurrency; i++ {
+		go func(i int) {
+			rng := rand.New(rand.NewSource(int64(i)))
+			rcv := c.Receiver()
+			defer wg.Done()
+			done := ctx.Done()
+
+			<-wait
+			for {
+				intended, ok := g.nextStart(i, done)
+				if !ok {
+					return
+				}
+				obj := g.objects[g.objectIndex(rng, len(g.objects))]
+				client, cldone := g.Client()
+				op := Operation{
+					OpType:   "PUT_TAGGING",
+					Thread:   uint16(i),
+					Size:     0,
+					File:     obj.Name,
+					ObjPerOp: 1,
+					Endpoint: client.EndpointURL().String(),
+					Intended: intended,
+				}
+				otags, err := tags.MapToObjectTags(RandomTags(rng, "warp", g.Tags, g.TagSize))
+				if err != nil {
+					g.Error("tag error: ", err)
+					cldone()
+					continue
+				}
+				op.Start = time.Now()
+				err = client.PutObjectTagging(nonTerm, g.bucketFor(obj.Name), obj.Name, otags, minio.PutObjectTaggingOptions{VersionID: obj.VersionID})
+				op.End = time.Now()
+				if err != nil {
+					g.Error("put tagging error: ", err)
+					op.Err = err.Error()
+				}
+				g.logOp(op, obj.VersionID)
+				rcv <- op
+
+				op.OpType = "GET_TAGGING"
+				op.Err = ""
+				op.Intended = time.Time{}
+				op.Start = time.Now()
+				got, err := client.GetObjectTagging(nonTerm, g.bucketFor(obj.Name), obj.Name, minio.GetObjectTaggingOptions{VersionID: obj.VersionID})
+				op.End = time.Now()
+				if err != nil {
+					g.Error("get tagging error: ", err)
+					op.Err = err.Error()
+				} else if n := len(got.ToMap()); n != g.Tags {
+					op.Err = fmt.Sprint("unexpected tag count. want:", g.Tags, ", got:", n)
+					g.Error(op.Err)
+				}
+				g.logOp(op, obj.VersionID)
+				rcv <- op
+				cldone()
+			}
+		}(i)
+	}
+	wg.Wait()
+	return c.Close(), nil
+}
+
+// Cleanup deletes everything uploaded to the bucket.
+func (g *Tagging) Cleanup(ctx context.Context) {
+	g.deleteAllInBucket(ctx, g.objects.Prefixes()...)
+}
@@ -0,0 +1,53 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/minio/minio-go/v7/pkg/tags"
+)
+
+func TestRandomTags(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	m := RandomTags(rng, "warp", 10, 32)
+	if len(m) != 10 {
+		t.Fatalf("got %d tags, want 10", len(m))
+	}
+	for i := 0; i < 10; i++ {
+		v, ok := m["warp-"+string(rune('0'+i))]
+		if !ok {
+			t.Fatalf("tag warp-%d missing", i)
+		}
+		if len(v) != 32 {
+			t.Errorf("tag warp-%d: value length %d, want 32", i, len(v))
+		}
+	}
+	if _, err := tags.MapToObjectTags(m); err != nil {
+		t.Errorf("tags not accepted as object tags: %v", err)
+	}
+	if reflect.DeepEqual(m, RandomTags(rng, "warp", 10, 32)) {
+		t.Error("same values returned twice")
+	}
+	// S3 allows at most 10 tags per object.
+	if _, err := tags.MapToObjectTags(RandomTags(rng, "warp", 11, 32)); err == nil {
+		t.Error("11 tags accepted as object tags")
+	}
+}
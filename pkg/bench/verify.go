@@ -0,0 +1,364 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"sync"
+
+	"github.com/minio/warp/pkg/generator"
+)
+
+// ErrKind classifies operation errors.
+type ErrKind string
+
+const (
+	// ErrKindNone is used for successful operations and request errors.
+	ErrKindNone ErrKind = ""
+	// ErrKindCorrupt is used when downloaded data does not match any uploaded version.
+	ErrKindCorrupt ErrKind = "corrupt"
+	// ErrKindStale is used when downloaded data matches another version of the object.
+	ErrKindStale ErrKind = "stale"
+	// ErrKindMissing is used when the latest version of an object was not found.
+	ErrKindMissing ErrKind = "missing"
+)
+
+const (
+	// verifyMinBlock is the smallest block size checksums are calculated for.
+	verifyMinBlock = 4 << 10
+	// verifyMaxBlocks is the maximum number of blocks per object.
+	verifyMaxBlocks = 1024
+	// verifyHistory is the number of previous uploads kept for unversioned objects.
+	verifyHistory = 2
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// VerifyError is returned when downloaded data could not be verified.
+type VerifyError struct {
+	Kind ErrKind
+	Msg  string
+}
+
+func (e *VerifyError) Error() string {
+	return fmt.Sprintf("verify: %s data: %s", e.Kind, e.Msg)
+}
+
+// Verifier keeps checksums of uploaded objects,
+// so the content of downloads can be verified.
+type Verifier struct {
+	mu   sync.RWMutex
+	objs map[string][]*objectSums
+	// seed of seeded content, if objects not uploaded by this verifier should be verified.
+	seed *int64
+}
+
+// objectSums contains checksums of an object version.
+type objectSums struct {
+	versionID string
+	size      int64
+	blockSize int64
+	sums      []uint32
+	// content is set instead of sums if the data can be regenerated.
+	content *generator.SeededContent
+}
+
+// NewVerifier returns an empty verifier.
+func NewVerifier() *Verifier {
+	return &Verifier{objs: make(map[string][]*objectSums)}
+}
+
+// NewSeededVerifier returns a verifier that also verifies objects it has not seen uploaded,
+// assuming they contain seeded content with the specified seed.
+// This allows verifying objects uploaded by other clients or runs.
+func NewSeededVerifier(seed int64) *Verifier {
+	v := NewVerifier()
+	v.seed = &seed
+	return v
+}
+
+// verifyBlockSize returns the block size used for an object of the specified size.
+func verifyBlockSize(size int64) int64 {
+	bs := int64(verifyMinBlock)
+	for size/bs > verifyMaxBlocks {
+		bs *= 2
+	}
+	return bs
+}
+
+// checksum calculates checksums of size bytes read from r.
+// The reader is rewound to the start.
+// If r returns seeded content of the object nothing is read,
+// since the content can be regenerated.
+func (v *Verifier) checksum(name string, r io.ReadSeeker, size int64) (*objectSums, error) {
+	s := objectSums{size: size, blockSize: verifyBlockSize(size)}
+	if c, ok := generator.SeededContentOf(r); ok && c.Name == name {
+		s.content = &c
+		return &s, nil
+	}
+	buf := make([]byte, s.blockSize)
+	for remain := size; remain > 0; {
+		n := s.blockSize
+		if remain < n {
+			n = remain
+		}
+		if _, err := io.ReadFull(r, buf[:n]); err != nil {
+			return nil, err
+		}
+		s.sums = append(s.sums, crc32.Checksum(buf[:n], crcTable))
+		remain -= n
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// add stores checksums of an uploaded object version.
+func (v *Verifier) add(name, versionID string, s *objectSums) {
+	s.versionID = versionID
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	vers := append(v.objs[name], s)
+	if versionID == "" && len(vers) > verifyHistory {
+		vers = vers[len(vers)-verifyHistory:]
+	}
+	v.objs[name] = vers
+}
+
+// remove forgets an object version.
+// Without a version all uploads of the object are forgotten.
+func (v *Verifier) remove(name, versionID string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if versionID == "" {
+		delete(v.objs, name)
+		return
+	}
+	vers := v.objs[name]
+	for i, s := range vers {
+		if s.versionID == versionID {
+			vers = append(vers[:i:i], vers[i+1:]...)
+			break
+		}
+	}
+	if len(vers) == 0 {
+		delete(v.objs, name)
+		return
+	}
+	v.objs[name] = vers
+}
+
+// find returns the checksums of an object version, or nil if unknown.
+// For unversioned objects the latest upload is returned.
+// Unknown objects are assumed to have seeded content of unknown size if the verifier has a seed.
+func (v *Verifier) find(name, versionID string) *objectSums {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	vers := v.objs[name]
+	if versionID == "" && len(vers) > 0 {
+		return vers[len(vers)-1]
+	}
+	for _, s := range vers {
+		if s.versionID == versionID {
+			return s
+		}
+	}
+	if v.seed != nil && len(vers) == 0 {
+		return &objectSums{
+			versionID: versionID,
+			size:      -1,
+			blockSize: verifyMinBlock,
+			content:   &generator.SeededContent{Seed: *v.seed, Name: name},
+		}
+	}
+	return nil
+}
+
+// matches returns whether b is the data of the object at offset pos.
+// scratch is used for regenerating seeded content.
+func (s *objectSums) matches(pos int64, b []byte, scratch []byte) bool {
+	if s.size >= 0 && pos+int64(len(b)) > s.size {
+		return false
+	}
+	if s.content != nil {
+		want := scratch[:len(b)]
+		s.content.ReadAt(want, pos)
+		return bytes.Equal(b, want)
+	}
+	if pos%s.blockSize != 0 {
+		return false
+	}
+	idx := int(pos / s.blockSize)
+	return idx < len(s.sums) && crc32.Checksum(b, crcTable) == s.sums[idx]
+}
+
+// alignRange extends an inclusive byte range so it contains whole verified blocks.
+func (v *Verifier) alignRange(name, versionID string, start, end int64) (int64, int64) {
+	s := v.find(name, versionID)
+	if s == nil {
+		return start, end
+	}
+	start -= start % s.blockSize
+	end = (end/s.blockSize+1)*s.blockSize - 1
+	// The size of seeded objects not seen uploaded is unknown.
+	if s.size >= 0 && end >= s.size {
+		end = s.size - 1
+	}
+	return start, end
+}
+
+// reader returns a reader that verifies the data read from r.
+// offset is the position of the first byte in the object and must be block aligned.
+// If the object is unknown r is returned.
+func (v *Verifier) reader(name, versionID string, r io.Reader, offset int64) io.Reader {
+	s := v.find(name, versionID)
+	if s == nil {
+		return r
+	}
+	return &verifyReader{
+		v:       v,
+		name:    name,
+		sums:    s,
+		r:       r,
+		offset:  offset,
+		buf:     make([]byte, 0, s.blockSize),
+		scratch: make([]byte, s.blockSize),
+	}
+}
+
+// verifyReader verifies data block by block as it is read.
+type verifyReader struct {
+	v      *Verifier
+	name   string
+	sums   *objectSums
+	r      io.Reader
+	offset int64
+	buf    []byte
+	// scratch is used for regenerating seeded content.
+	scratch []byte
+}
+
+func (r *verifyReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	for b := p[:n]; len(b) > 0; {
+		add := cap(r.buf) - len(r.buf)
+		if add > len(b) {
+			add = len(b)
+		}
+		r.buf = append(r.buf, b[:add]...)
+		b = b[add:]
+		if len(r.buf) == cap(r.buf) {
+			if verr := r.verifyBlock(); verr != nil {
+				return n, verr
+			}
+		}
+	}
+	if errors.Is(err, io.EOF) && len(r.buf) > 0 {
+		if verr := r.verifyBlock(); verr != nil {
+			return n, verr
+		}
+	}
+	return n, err
+}
+
+// verifyBlock verifies the buffered block.
+func (r *verifyReader) verifyBlock() error {
+	pos := r.offset
+	b := r.buf
+	r.offset += int64(len(r.buf))
+	r.buf = r.buf[:0]
+	if r.sums.matches(pos, b, r.scratch) {
+		return nil
+	}
+	if r.sums.size >= 0 && pos+int64(len(b)) > r.sums.size {
+		return &VerifyError{Kind: ErrKindCorrupt, Msg: fmt.Sprintf("data beyond object size %d at offset %d", r.sums.size, pos)}
+	}
+	// Check if data belongs to another version.
+	r.v.mu.RLock()
+	defer r.v.mu.RUnlock()
+	for _, s := range r.v.objs[r.name] {
+		if s == r.sums || !s.matches(pos, b, r.scratch) {
+			continue
+		}
+		if s.versionID == "" {
+			return &VerifyError{Kind: ErrKindStale, Msg: fmt.Sprintf("offset %d matches a previous upload", pos)}
+		}
+		return &VerifyError{Kind: ErrKindStale, Msg: fmt.Sprintf("offset %d matches version %q, want version %q", pos, s.versionID, r.sums.versionID)}
+	}
+	return &VerifyError{Kind: ErrKindCorrupt, Msg: fmt.Sprintf("checksum mismatch at offset %d", pos)}
+}
+
+// errKind returns the kind of an error.
+func errKind(err error) ErrKind {
+	var verr *VerifyError
+	if errors.As(err, &verr) {
+		return verr.Kind
+	}
+	return ErrKindNone
+}
+
+// verifySums calculates checksums of the object content if verification is enabled.
+func (c *Common) verifySums(obj *generator.Object) (*objectSums, error) {
+	if c.Verify == nil {
+		return nil, nil
+	}
+	sums, err := c.Verify.checksum(obj.Name, obj.Reader, obj.Size)
+	if err != nil {
+		return nil, fmt.Errorf("generator error: %w", err)
+	}
+	return sums, nil
+}
+
+// verifyAdd stores the checksums of an uploaded object if verification is enabled.
+func (c *Common) verifyAdd(obj generator.Object, sums *objectSums) {
+	if c.Verify == nil || sums == nil {
+		return
+	}
+	c.Verify.add(obj.Name, obj.VersionID, sums)
+}
+
+// verifyRemove forgets a deleted object if verification is enabled.
+func (c *Common) verifyRemove(obj generator.Object) {
+	if c.Verify == nil {
+		return
+	}
+	c.Verify.remove(obj.Name, obj.VersionID)
+}
+
+// verifyRange aligns an inclusive byte range of an object to verified blocks
+// if verification is enabled.
+func (c *Common) verifyRange(obj generator.Object, start, end int64) (int64, int64) {
+	if c.Verify == nil {
+		return start, end
+	}
+	return c.Verify.alignRange(obj.Name, obj.VersionID, start, end)
+}
+
+// verifyReader returns a reader verifying the content of the object read from r
+// if verification is enabled. offset is the position of the first byte read.
+func (c *Common) verifyReader(obj generator.Object, r io.Reader, offset int64) io.Reader {
+	if c.Verify == nil {
+		return r
+	}
+	return c.Verify.reader(obj.Name, obj.VersionID, r, offset)
+}
@@ -0,0 +1,202 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"testing"
+
+	"github.com/minio/warp/pkg/generator"
+)
+
+func TestVerifier(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	v1 := make([]byte, 100<<10+123)
+	v2 := make([]byte, len(v1))
+	rng.Read(v1)
+	rng.Read(v2)
+
+	v := NewVerifier()
+	for _, data := range [][]byte{v1, v2} {
+		sums, err := v.checksum("obj", bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		v.add("obj", "", sums)
+	}
+	read := func(data []byte, offset int64) ErrKind {
+		_, err := io.Copy(ioutil.Discard, v.reader("obj", "", bytes.NewReader(data), offset))
+		if err != nil && errKind(err) == ErrKindNone {
+			t.Fatal(err)
+		}
+		return errKind(err)
+	}
+	if got := read(v2, 0); got != ErrKindNone {
+		t.Errorf("latest upload: got %q", got)
+	}
+	if got := read(v1, 0); got != ErrKindStale {
+		t.Errorf("previous upload: got %q", got)
+	}
+	corrupt := append([]byte{}, v2...)
+	corrupt[len(corrupt)-1]++
+	if got := read(corrupt, 0); got != ErrKindCorrupt {
+		t.Errorf("corrupt upload: got %q", got)
+	}
+
+	start, end := v.alignRange("obj", "", 5000, 20000)
+	if got := read(v2[start:end+1], start); got != ErrKindNone {
+		t.Errorf("range %d-%d: got %q", start, end, got)
+	}
+	start, end = v.alignRange("obj", "", 90000, int64(len(v2))-1)
+	if got := read(v2[start:end+1], start); got != ErrKindNone {
+		t.Errorf("range %d-%d: got %q", start, end, got)
+	}
+}
+
+func TestVerifierRemove(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	v := NewVerifier()
+	put := func() []byte {
+		data := make([]byte, 10<<10)
+		rng.Read(data)
+		sums, err := v.checksum("obj", bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		v.add("obj", "", sums)
+		return data
+	}
+	put()
+	v2 := put()
+	// Deleting an unversioned object removes all previous uploads.
+	v.remove("obj", "")
+	if got := v.find("obj", ""); got != nil {
+		t.Fatalf("deleted object still known: %+v", got)
+	}
+	v3 := put()
+	if got := len(v.objs["obj"]); got != 1 {
+		t.Fatalf("want 1 upload, got %d", got)
+	}
+	read := func(data []byte) ErrKind {
+		_, err := io.Copy(ioutil.Discard, v.reader("obj", "", bytes.NewReader(data), 0))
+		if err != nil && errKind(err) == ErrKindNone {
+			t.Fatal(err)
+		}
+		return errKind(err)
+	}
+	if got := read(v3); got != ErrKindNone {
+		t.Errorf("latest upload: got %q", got)
+	}
+	if got := read(v2); got != ErrKindCorrupt {
+		t.Errorf("upload before delete: got %q", got)
+	}
+}
+
+func TestSeededVerifier(t *testing.T) {
+	const size = 100<<10 + 123
+	content := func(name string) []byte {
+		b := make([]byte, size)
+		generator.SeededContent{Seed: 1, Name: name}.ReadAt(b, 0)
+		return b
+	}
+	v := NewSeededVerifier(1)
+	read := func(name string, data []byte, offset int64) ErrKind {
+		_, err := io.Copy(ioutil.Discard, v.reader(name, "", bytes.NewReader(data), offset))
+		if err != nil && errKind(err) == ErrKindNone {
+			t.Fatal(err)
+		}
+		return errKind(err)
+	}
+
+	// Objects that have not been uploaded are verified using the seed.
+	if got := read("other", content("other"), 0); got != ErrKindNone {
+		t.Errorf("unknown object: got %q", got)
+	}
+	if got := read("other", content("other")[1234:5678], 1234); got != ErrKindNone {
+		t.Errorf("unknown object range: got %q", got)
+	}
+	if got := read("other", content("obj"), 0); got != ErrKindCorrupt {
+		t.Errorf("unknown object, other content: got %q", got)
+	}
+	// The size of unknown objects is not known, so ranges are not limited by it.
+	start, end := v.alignRange("other", "", 1234, 5678)
+	if start > 1234 || end < 5678 {
+		t.Fatalf("unknown object range 1234-5678 aligned to %d-%d", start, end)
+	}
+	if got := read("other", content("other")[start:end+1], start); got != ErrKindNone {
+		t.Errorf("unknown object aligned range: got %q", got)
+	}
+
+	// Uploaded objects keep no checksums.
+	r := generator.SeededContent{Seed: 1, Name: "obj"}.Reader(size)
+	sums, err := v.checksum("obj", r, size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sums.content == nil || len(sums.sums) != 0 {
+		t.Fatal("checksums calculated for seeded content")
+	}
+	v.add("obj", "", sums)
+	if got := read("obj", content("obj"), 0); got != ErrKindNone {
+		t.Errorf("uploaded object: got %q", got)
+	}
+	if got := read("obj", append(content("obj"), 0), 0); got != ErrKindCorrupt {
+		t.Errorf("uploaded object, too long: got %q", got)
+	}
+	corrupt := content("obj")
+	corrupt[size/2]++
+	if got := read("obj", corrupt, 0); got != ErrKindCorrupt {
+		t.Errorf("corrupt object: got %q", got)
+	}
+
+	// Every version of an overwritten object has its own content.
+	versions := make([][]byte, 2)
+	for i := range versions {
+		obj := generator.Object{Name: "new", Size: size, Reader: generator.SeededContent{Seed: 1, Name: "new"}.Reader(size)}
+		obj.Reseed("ver", uint64(i))
+		sums, err := v.checksum(obj.Name, obj.Reader, obj.Size)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sums.content == nil || sums.content.Version != uint64(i) {
+			t.Fatalf("version %d: unexpected content %+v", i, sums.content)
+		}
+		v.add(obj.Name, fmt.Sprint("v", i), sums)
+		versions[i], err = ioutil.ReadAll(obj.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	readVersion := func(versionID string, data []byte) ErrKind {
+		_, err := io.Copy(ioutil.Discard, v.reader("ver", versionID, bytes.NewReader(data), 0))
+		if err != nil && errKind(err) == ErrKindNone {
+			t.Fatal(err)
+		}
+		return errKind(err)
+	}
+	if got := readVersion("v1", versions[1]); got != ErrKindNone {
+		t.Errorf("latest version: got %q", got)
+	}
+	if got := readVersion("v1", versions[0]); got != ErrKindStale {
+		t.Errorf("previous version: got %q", got)
+	}
+}
@@ -54,13 +54,16 @@ func (g *Versioned) Prepare(ctx context.Context) error {
 	if err := g.createEmptyBucket(ctx); err != nil {
 		return err
 	}
-	if !g.Versioned {
+	// With several buckets only some may have versioning enabled.
+	if !g.Versioned || g.BucketSet != nil {
 		cl, done := g.Client()
-		err := cl.EnableVersioning(ctx, g.Bucket)
-		done()
-		if err != nil {
-			return err
+		for _, bucket := range g.buckets() {
+			if err := cl.EnableVersioning(ctx, bucket); err != nil {
+				done()
+				return err
+			}
 		}
+		done()
 		g.Versioned = true
 	}
 	src := g.Source()
@@ -90,8 +93,29 @@ func (g *Versioned) Prepare(ctx context.Context) error {
 				}
 				obj := src.Object()
 				client, clDone := g.Client()
+				op := Operation{
+					OpType:   http.MethodPut,
+					Thread:   uint16(i),
+					Size:     obj.Size,
+					File:     obj.Name,
+					ObjPerOp: 1,
+					Endpoint: client.EndpointURL().String(),
+				}
 				opts.ContentType = obj.ContentType
-				res, err := client.PutObject(ctx, g.Bucket, obj.Name, obj.Reader, obj.Size, opts)
+				sums, err := g.verifySums(obj)
+				if err != nil {
+					g.Error(err)
+					mu.Lock()
+					if groupErr == nil {
+						groupErr = err
+					}
+					mu.Unlock()
+					clDone()
+					return
+				}
+				op.Start = time.Now()
+				res, err := client.PutObject(ctx, g.bucketFor(obj.Name), obj.Name, obj.Reader, obj.Size, opts)
+				op.End = time.Now()
 				if err != nil {
 					err := fmt.Errorf("upload error: %w", err)
 					g.Error(err)
@@ -100,6 +124,8 @@ func (g *Versioned) Prepare(ctx context.Context) error {
 						groupErr = err
 					}
 					mu.Unlock()
+					op.Err = err.Error()
+					g.logOp(op, "")
 					return
 				}
 				obj.VersionID = res.VersionID
@@ -111,8 +137,12 @@ func (g *Versioned) Prepare(ctx context.Context) error {
 						groupErr = err
 					}
 					mu.Unlock()
+					op.Err = err.Error()
+					g.logOp(op, obj.VersionID)
 					return
 				}
+				g.verifyAdd(*obj, sums)
+				g.logOp(op, obj.VersionID)
 				clDone()
 				obj.Reader = nil
 				g.Dist.addObj(*obj)
@@ -129,7 +159,7 @@ func (g *Versioned) Prepare(ctx context.Context) error {
 func (g *Versioned) Start(ctx context.Context, wait chan struct{}) (Operations, error) {
 	var wg sync.WaitGroup
 	wg.Add(g.Concurrency)
-	c := g.Collector
+	c := g.Collector.reopen()
 	if g.AutoTermDur > 0 {
 		ctx = c.AutoTerm(ctx, "", g.AutoTermScale, autoTermCheck, autoTermSamples, g.AutoTermDur)
 	}
@@ -147,10 +177,9 @@ func (g *Versioned) Start(ctx context.Context, wait chan struct{}) (Operations,
 
 			<-wait
 			for {
-				select {
-				case <-done:
+				intended, ok := g.nextStart(i, done)
+				if !ok {
 					return
-				default:
 				}
 				operation := g.Dist.getOp()
 				switch operation {
@@ -165,24 +194,27 @@ func (g *Versioned) Start(ctx context.Context, wait chan struct{}) (Operations,
 						File:     obj.Name,
 						ObjPerOp: 1,
 						Endpoint: client.EndpointURL().String(),
+						Intended: intended,
 					}
 					op.Start = time.Now()
 					var err error
 					getOpts.VersionID = obj.VersionID
-					fbr.r, err = client.GetObject(nonTerm, g.Bucket, obj.Name, getOpts)
+					fbr.r, err = client.GetObject(nonTerm, g.bucketFor(obj.Name), obj.Name, getOpts)
 					if err != nil {
 						g.Error("download error: ", err)
 						op.Err = err.Error()
 						op.End = time.Now()
+						g.logOp(op, obj.VersionID)
 						rcv <- op
 						clDone()
 						objDone()
 						continue
 					}
-					n, err := io.Copy(ioutil.Discard, &fbr)
+					n, err := io.Copy(ioutil.Discard, g.verifyReader(obj, &fbr, 0))
 					if err != nil {
 						g.Error("download error: ", err)
 						op.Err = err.Error()
+						op.ErrKind = errKind(err)
 					}
 					op.FirstByte = fbr.t
 					op.End = time.Now()
@@ -190,6 +222,7 @@ func (g *Versioned) Start(ctx context.Context, wait chan struct{}) (Operations,
 						op.Err = fmt.Sprint("unexpected download size. want:", obj.Size, ", got:", n)
 						g.Error(op.Err)
 					}
+					g.logOp(op, obj.VersionID)
 					rcv <- op
 					objDone()
 					clDone()
@@ -204,9 +237,17 @@ func (g *Versioned) Start(ctx context.Context, wait chan struct{}) (Operations,
 						File:     obj.Name,
 						ObjPerOp: 1,
 						Endpoint: client.EndpointURL().String(),
+						Intended: intended,
+					}
+					sums, err := g.verifySums(&obj)
+					if err != nil {
+						g.Error(err)
+						clDone()
+						objDone("")
+						continue
 					}
 					op.Start = time.Now()
-					res, err := client.PutObject(nonTerm, g.Bucket, obj.Name, obj.Reader, obj.Size, putOpts)
+					res, err := client.PutObject(nonTerm, g.bucketFor(obj.Name), obj.Name, obj.Reader, obj.Size, putOpts)
 					op.End = time.Now()
 					if err != nil {
 						g.Error("upload error: ", err)
@@ -226,8 +267,11 @@ func (g *Versioned) Start(ctx context.Context, wait chan struct{}) (Operations,
 					if op.Err != "" {
 						// Don't add if error.
 						res.VersionID = ""
+					} else {
+						g.verifyAdd(obj, sums)
 					}
 					objDone(res.VersionID)
+					g.logOp(op, obj.VersionID)
 					rcv <- op
 				case http.MethodDelete:
 					client, clDone := g.Client()
@@ -239,15 +283,19 @@ func (g *Versioned) Start(ctx context.Context, wait chan struct{}) (Operations,
 						File:     obj.Name,
 						ObjPerOp: 1,
 						Endpoint: client.EndpointURL().String(),
+						Intended: intended,
 					}
 					op.Start = time.Now()
-					err := client.RemoveObject(nonTerm, g.Bucket, obj.Name, minio.RemoveObjectOptions{VersionID: obj.VersionID})
+					err := client.RemoveObject(nonTerm, g.bucketFor(obj.Name), obj.Name, minio.RemoveObjectOptions{VersionID: obj.VersionID})
 					op.End = time.Now()
 					clDone()
 					if err != nil {
 						g.Error("delete error:", err)
 						op.Err = err.Error()
+					} else {
+						g.verifyRemove(obj)
 					}
+					g.logOp(op, obj.VersionID)
 					rcv <- op
 				case "STAT":
 					obj, objDone := g.Dist.randomObjRead()
@@ -259,11 +307,12 @@ func (g *Versioned) Start(ctx context.Context, wait chan struct{}) (Operations,
 						File:     obj.Name,
 						ObjPerOp: 1,
 						Endpoint: client.EndpointURL().String(),
+						Intended: intended,
 					}
 					op.Start = time.Now()
 					var err error
 					statOpts.VersionID = obj.VersionID
-					objI, err := client.StatObject(nonTerm, g.Bucket, obj.Name, statOpts)
+					objI, err := client.StatObject(nonTerm, g.bucketFor(obj.Name), obj.Name, statOpts)
 					if err != nil {
 						g.Error("stat error:", err)
 						op.Err = err.Error()
@@ -273,6 +322,7 @@ func (g *Versioned) Start(ctx context.Context, wait chan struct{}) (Operations,
 						op.Err = fmt.Sprint("unexpected stat size. want:", obj.Size, ", got:", objI.Size)
 						g.Error(op.Err)
 					}
+					g.logOp(op, obj.VersionID)
 					rcv <- op
 					objDone()
 					clDone()
@@ -293,6 +343,8 @@ func (g *Versioned) Cleanup(ctx context.Context) {
 
 type versionedObj struct {
 	objs generator.Objects
+	// writes is the number of versions written.
+	writes uint64
 }
 
 // VersionedDistribution keeps track of operation distribution
@@ -405,8 +457,13 @@ func (m *VersionedDistribution) newVersion(o *generator.Object) (obj generator.O
 	// We keep 'r' until we have finished adding a new version.
 	// Otherwise we risk it being deleted.
 	r, rdone := m.randomObjRead()
+	m.mu.Lock()
+	vo := m.objects[r.Name]
+	vo.writes++
+	m.objects[r.Name] = vo
+	m.mu.Unlock()
 	o2.VersionID = ""
-	o2.Name = r.Name
+	o2.Reseed(r.Name, vo.writes)
 	o2.Prefix = r.Prefix
 	return o2, func(versionID string) {
 		if versionID != "" {
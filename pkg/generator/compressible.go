@@ -0,0 +1,266 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sync/atomic"
+)
+
+// WithCompressible returns options for data with a target compression ratio
+// and a percentage of duplicate blocks.
+func WithCompressible() CompressibleOpts {
+	return compressibleOptsDefaults()
+}
+
+// Apply compressible data options.
+func (o CompressibleOpts) Apply() Option {
+	return func(opts *Options) error {
+		if err := o.validate(); err != nil {
+			return err
+		}
+		opts.compressible = o
+		opts.src = newCompressible
+		return nil
+	}
+}
+
+func (o CompressibleOpts) validate() error {
+	if o.ratio < 1 {
+		return errors.New("compressible: ratio must be >= 1")
+	}
+	if o.dedup < 0 || o.dedup > 100 {
+		return errors.New("compressible: dedup percentage must be between 0 and 100")
+	}
+	if o.blockSize < compressSegment || o.blockSize%compressSegment != 0 {
+		return fmt.Errorf("compressible: block size must be a multiple of %d", compressSegment)
+	}
+	return nil
+}
+
+// Ratio sets the target compression ratio.
+// 1 is incompressible, 2 compresses to half the size.
+func (o CompressibleOpts) Ratio(r float64) CompressibleOpts {
+	o.ratio = r
+	return o
+}
+
+// Dedup sets the percentage of blocks that are duplicates of other blocks.
+func (o CompressibleOpts) Dedup(pct float64) CompressibleOpts {
+	o.dedup = pct
+	return o
+}
+
+// BlockSize sets the size of duplicate blocks.
+// Dedup is only detected by storage using the same or a smaller block size.
+func (o CompressibleOpts) BlockSize(n int) CompressibleOpts {
+	o.blockSize = n
+	return o
+}
+
+// CompressibleOpts are the options for the compressible data source.
+type CompressibleOpts struct {
+	ratio     float64
+	dedup     float64
+	blockSize int
+}
+
+func compressibleOptsDefaults() CompressibleOpts {
+	return CompressibleOpts{
+		ratio:     2,
+		dedup:     0,
+		blockSize: 4 << 10,
+	}
+}
+
+const (
+	// compressSegment is the size of segments that each have the compression ratio,
+	// so the ratio does not depend on the window of the compressor.
+	compressSegment = 256
+	// compressOverhead is the approximate number of bytes
+	// a compressor needs for encoding the compressible part of a segment.
+	compressOverhead = 4
+	// dedupBlocks is the number of distinct duplicate blocks.
+	// The blocks are the same for all sources with the same block size,
+	// so duplicates are also found across objects and clients.
+	dedupBlocks = 64
+)
+
+// dedupKey is the key of the duplicate blocks.
+var dedupKey = []byte("warp dedup block")
+
+type compressibleSrc struct {
+	counter uint64
+	o       Options
+	rng     *rand.Rand
+	obj     Object
+	dedup   cipher.Block
+	// literals is the number of incompressible bytes per segment.
+	literals int
+}
+
+func newCompressible(o Options) (Source, error) {
+	dedup, err := aes.NewCipher(dedupKey)
+	if err != nil {
+		return nil, err
+	}
+	// A segment compresses to roughly its literals plus the overhead.
+	literals := int(math.Round(compressSegment/o.compressible.ratio)) - compressOverhead
+	if literals < 1 {
+		literals = 1
+	}
+	if o.compressible.ratio == 1 {
+		literals = compressSegment
+	}
+	s := compressibleSrc{
+		o:        o,
+		rng:      rand.New(rand.NewSource(int64(rand.Uint64()))),
+		dedup:    dedup,
+		literals: literals,
+		obj: Object{
+			ContentType: "application/octet-stream",
+		},
+	}
+	s.obj.setPrefix(o)
+	return &s, nil
+}
+
+func (s *compressibleSrc) Object() *Object {
+	atomic.AddUint64(&s.counter, 1)
+	var nBuf [16]byte
+	randASCIIBytes(nBuf[:], s.rng)
+	s.obj.Size = s.o.getSize(s.rng)
+	s.obj.setName(s.o, s.rng, fmt.Sprintf("%d.%s.rnd", atomic.LoadUint64(&s.counter), string(nBuf[:])))
+	var key [16]byte
+	s.rng.Read(key[:])
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		panic(err)
+	}
+	s.obj.Reader = &compressibleReader{
+		src:   s,
+		block: block,
+		size:  s.obj.Size,
+		buf:   make([]byte, s.o.compressible.blockSize),
+		idx:   -1,
+	}
+	return &s.obj
+}
+
+func (s *compressibleSrc) String() string {
+	c := s.o.compressible
+	desc := fmt.Sprintf("Compressible data; ratio %.1f, %.0f%% duplicate %d byte blocks", c.ratio, c.dedup, c.blockSize)
+	if s.o.randSize {
+		return fmt.Sprintf("%s; random size up to %d bytes", desc, s.o.totalSize)
+	}
+	return fmt.Sprintf("%s; %d bytes total", desc, s.o.totalSize)
+}
+
+func (s *compressibleSrc) Prefix() string {
+	return s.obj.sourcePrefix(s.o)
+}
+
+// compressibleReader generates the content of an object block by block.
+type compressibleReader struct {
+	src   *compressibleSrc
+	block cipher.Block
+	size  int64
+	pos   int64
+	// buf contains block idx.
+	buf []byte
+	idx int64
+}
+
+// fill generates block idx into buf.
+func (r *compressibleReader) fill(idx int64) {
+	c := r.src.o.compressible
+	bs := int64(len(r.buf))
+	// Decide pseudo-randomly if the block is a duplicate, so it is the same after a seek.
+	var iv, choice [aes.BlockSize]byte
+	binary.BigEndian.PutUint64(iv[:], uint64(idx))
+	r.block.Encrypt(choice[:], iv[:])
+	block, offset := r.block, idx*bs
+	if float64(binary.LittleEndian.Uint64(choice[:8])>>11)/(1<<53) < c.dedup/100 {
+		block = r.src.dedup
+		offset = int64(binary.LittleEndian.Uint64(choice[8:])%dedupBlocks) * bs
+	}
+	binary.BigEndian.PutUint64(iv[:], 1)
+	binary.BigEndian.PutUint64(iv[8:], uint64(offset/aes.BlockSize))
+	for i := range r.buf {
+		r.buf[i] = 0
+	}
+	// Only the literals of each segment are random, the rest are zeros.
+	stream := cipher.NewCTR(block, iv[:])
+	for seg := r.buf; len(seg) > 0; seg = seg[compressSegment:] {
+		lit := seg[:r.src.literals]
+		stream.XORKeyStream(lit, lit)
+	}
+	r.idx = idx
+}
+
+func (r *compressibleReader) Read(p []byte) (n int, err error) {
+	bs := int64(len(r.buf))
+	for len(p) > 0 {
+		if r.pos >= r.size {
+			if n == 0 {
+				err = io.EOF
+			}
+			return n, err
+		}
+		idx := r.pos / bs
+		if idx != r.idx {
+			r.fill(idx)
+		}
+		data := r.buf[r.pos%bs:]
+		if remain := r.size - r.pos; int64(len(data)) > remain {
+			data = data[:remain]
+		}
+		copied := copy(p, data)
+		p = p[copied:]
+		r.pos += int64(copied)
+		n += copied
+	}
+	return n, nil
+}
+
+func (r *compressibleReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	default:
+		return 0, errors.New("compressibleReader.Seek: invalid whence")
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.pos
+	case io.SeekEnd:
+		offset += r.size
+	}
+	if offset < 0 {
+		return 0, errors.New("compressibleReader.Seek: negative position")
+	}
+	if offset > r.size {
+		return 0, io.EOF
+	}
+	r.pos = offset
+	return offset, nil
+}
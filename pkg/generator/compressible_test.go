@@ -0,0 +1,98 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"math"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestCompressible(t *testing.T) {
+	const size = 4 << 20
+	enc, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ratio := range []float64{1, 2, 4} {
+		src, err := New(WithCompressible().Ratio(ratio).Apply(), WithSize(size))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadAll(src.Object().Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) != size {
+			t.Fatalf("want size %d, got %d", size, len(b))
+		}
+		var gz bytes.Buffer
+		w := gzip.NewWriter(&gz)
+		w.Write(b)
+		w.Close()
+		for name, got := range map[string]float64{
+			"gzip": float64(len(b)) / float64(gz.Len()),
+			"zstd": float64(len(b)) / float64(len(enc.EncodeAll(b, nil))),
+		} {
+			if math.Abs(got-ratio)/ratio > 0.1 {
+				t.Errorf("ratio %v: %s compressed %.2f", ratio, name, got)
+			}
+		}
+	}
+}
+
+func TestCompressibleDedup(t *testing.T) {
+	const size, bs = 4 << 20, 4 << 10
+	src, err := New(WithCompressible().Dedup(50).BlockSize(bs).Apply(), WithSize(size))
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := src.Object()
+	b, err := ioutil.ReadAll(obj.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks := make(map[string]struct{})
+	for i := 0; i < size; i += bs {
+		blocks[string(b[i:i+bs])] = struct{}{}
+	}
+	dups := float64(size/bs-len(blocks)) / float64(size/bs)
+	// The first use of each of the duplicate blocks is unique.
+	if dups < 0.4 || dups > 0.55 {
+		t.Errorf("want about 50%% duplicate blocks, got %.1f%%", dups*100)
+	}
+
+	// The same data must be returned after seeking.
+	for _, off := range []int64{0, 1, bs - 1, bs, size - 10} {
+		if _, err := obj.Reader.Seek(off, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		got, err := ioutil.ReadAll(obj.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, b[off:]) {
+			t.Errorf("offset %d: content after seek differs", off)
+		}
+	}
+}
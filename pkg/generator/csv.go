@@ -162,7 +162,7 @@ func (c *csvSource) Object() *Object {
 
 	var nBuf [16]byte
 	randASCIIBytes(nBuf[:], c.rng)
-	c.obj.setName(string(nBuf[:]) + ".csv")
+	c.obj.setName(c.o, c.rng, string(nBuf[:])+".csv")
 	return &c.obj
 
 }
@@ -172,5 +172,5 @@ func (c *csvSource) String() string {
 }
 
 func (c *csvSource) Prefix() string {
-	return c.obj.Prefix
+	return c.obj.sourcePrefix(c.o)
 }
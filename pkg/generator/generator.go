@@ -39,6 +39,7 @@ type Source interface {
 	String() string
 
 	// Prefix returns the prefix if any.
+	// If objects are spread over several prefixes an empty string is returned.
 	Prefix() string
 }
 
@@ -103,7 +104,20 @@ func (o *Object) setPrefix(opts Options) {
 	o.Prefix = string(b)
 }
 
-func (o *Object) setName(s string) {
+// sourcePrefix returns the prefix of all objects of a source.
+func (o *Object) sourcePrefix(opts Options) string {
+	if opts.prefixes != nil {
+		return ""
+	}
+	return o.Prefix
+}
+
+// setName sets the name of the object from the name chosen by the generator.
+func (o *Object) setName(opts Options, rng *rand.Rand, s string) {
+	if opts.prefixes != nil {
+		o.Prefix = opts.prefixes.pick(rng)
+	}
+	s = opts.keys.name(rng, s)
 	if len(o.Prefix) == 0 {
 		o.Name = s
 		return
@@ -123,6 +137,13 @@ func New(opts ...Option) (Source, error) {
 	if options.src == nil {
 		return nil, errors.New("internal error: generator Source was nil")
 	}
+	if options.prefixFanout > 0 {
+		p, err := newPrefixSet(options.prefixFanout, options.randomPrefix, options.prefixSkew)
+		if err != nil {
+			return nil, err
+		}
+		options.prefixes = p
+	}
 	return options.src(options)
 }
 
@@ -138,6 +159,13 @@ func NewFn(opts ...Option) (func() Source, error) {
 	if options.src == nil {
 		return nil, errors.New("internal error: generator Source was nil")
 	}
+	if options.prefixFanout > 0 {
+		p, err := newPrefixSet(options.prefixFanout, options.randomPrefix, options.prefixSkew)
+		if err != nil {
+			return nil, err
+		}
+		options.prefixes = p
+	}
 
 	return func() Source {
 		s, err := options.src(options)
@@ -0,0 +1,234 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// maxKeyLength is the maximum length of an S3 object key.
+const maxKeyLength = 1024
+
+// Characters used for UTF-8 keys.
+// Mixes 2, 3 and 4 byte encodings as well as right-to-left scripts.
+var utf8KeyRunes = []rune("äöüßéñçøåłžΩπλжщ日本語中文한국어עבריתالعربية😀🚀🎉")
+
+// Characters used for special character keys.
+// These must be escaped in URLs or signatures.
+var specialKeyRunes = []rune(" !\"#$%&'()*+,;=?@[\\]^`{|}~<>")
+
+// KeyOpts provides options for object key naming.
+type KeyOpts struct {
+	template string
+	depth    int
+	fanout   int
+	length   int
+}
+
+// WithKeys returns object key naming options.
+// The template can contain these placeholders:
+//
+//	{name}    the name chosen by the generator.
+//	{rand}    16 random ASCII characters.
+//	{seq}     a zero padded sequence number shared by all sources.
+//	{time}    the time of creation in UTC, so keys are ordered by time.
+//	{hash}    16 hexadecimal characters, hashed from the sequence number.
+//	{dirs}    a directory hierarchy of the configured depth and fan-out.
+//	{utf8}    8 random non-ASCII UTF-8 characters.
+//	{special} 8 random characters that must be escaped in URLs.
+func WithKeys() KeyOpts {
+	return keyOptsDefaults()
+}
+
+func keyOptsDefaults() KeyOpts {
+	return KeyOpts{
+		template: "{name}",
+		fanout:   10,
+	}
+}
+
+// Template sets the key template.
+func (k KeyOpts) Template(s string) KeyOpts {
+	k.template = s
+	return k
+}
+
+// Depth sets the number of directory levels of {dirs}.
+// If the template has no {dirs} the directories are added before the key.
+func (k KeyOpts) Depth(n int) KeyOpts {
+	k.depth = n
+	return k
+}
+
+// Fanout sets the number of directories at each level of {dirs}.
+func (k KeyOpts) Fanout(n int) KeyOpts {
+	k.fanout = n
+	return k
+}
+
+// Length will pad keys with random ASCII characters up to this length.
+func (k KeyOpts) Length(n int) KeyOpts {
+	k.length = n
+	return k
+}
+
+// Apply key options.
+func (k KeyOpts) Apply() Option {
+	return func(opts *Options) error {
+		t, err := newKeyTemplate(k)
+		if err != nil {
+			return err
+		}
+		opts.keys = t
+		return nil
+	}
+}
+
+// keyTemplate is a parsed key template.
+type keyTemplate struct {
+	opts  KeyOpts
+	parts []keyPart
+	// seq is shared between all sources.
+	seq  *uint64
+	salt [8]byte
+}
+
+// keyPart is literal text or a placeholder.
+type keyPart struct {
+	literal     string
+	placeholder string
+}
+
+func newKeyTemplate(k KeyOpts) (*keyTemplate, error) {
+	if k.depth < 0 {
+		return nil, errors.New("keys: depth must be >= 0")
+	}
+	if k.depth > 0 && k.fanout <= 0 {
+		return nil, errors.New("keys: fanout must be > 0")
+	}
+	if k.length < 0 || k.length > maxKeyLength {
+		return nil, fmt.Errorf("keys: length must be between 0 and %d", maxKeyLength)
+	}
+	if k.depth > 0 && !strings.Contains(k.template, "{dirs}") {
+		k.template = "{dirs}/" + k.template
+	}
+	t := keyTemplate{opts: k, seq: new(uint64)}
+	binary.LittleEndian.PutUint64(t.salt[:], rand.Uint64())
+	var unique bool
+	s := k.template
+	for len(s) > 0 {
+		start := strings.IndexByte(s, '{')
+		if start < 0 {
+			t.parts = append(t.parts, keyPart{literal: s})
+			break
+		}
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("keys: unterminated placeholder in %q", k.template)
+		}
+		if start > 0 {
+			t.parts = append(t.parts, keyPart{literal: s[:start]})
+		}
+		p := s[start+1 : start+end]
+		switch p {
+		case "name", "rand", "seq", "hash":
+			unique = true
+		case "time", "utf8", "special":
+		case "dirs":
+			if k.depth == 0 {
+				return nil, errors.New("keys: {dirs} requires depth > 0")
+			}
+		default:
+			return nil, fmt.Errorf("keys: unknown placeholder {%s}", p)
+		}
+		t.parts = append(t.parts, keyPart{placeholder: p})
+		s = s[start+end+1:]
+	}
+	if !unique {
+		return nil, fmt.Errorf("keys: template %q must contain {name}, {rand}, {seq} or {hash} to create unique keys", k.template)
+	}
+	return &t, nil
+}
+
+// name returns the key of an object the generator would have named name.
+func (t *keyTemplate) name(rng *rand.Rand, name string) string {
+	if t == nil {
+		return name
+	}
+	var sb strings.Builder
+	var seq uint64
+	seqFn := func() uint64 {
+		if seq == 0 {
+			seq = atomic.AddUint64(t.seq, 1)
+		}
+		return seq
+	}
+	for _, p := range t.parts {
+		switch p.placeholder {
+		case "":
+			sb.WriteString(p.literal)
+		case "name":
+			sb.WriteString(name)
+		case "rand":
+			var b [16]byte
+			randASCIIBytes(b[:], rng)
+			sb.Write(b[:])
+		case "seq":
+			fmt.Fprintf(&sb, "%012d", seqFn())
+		case "time":
+			sb.WriteString(time.Now().UTC().Format("20060102T150405.000000000Z"))
+		case "hash":
+			var b [16]byte
+			copy(b[:8], t.salt[:])
+			binary.LittleEndian.PutUint64(b[8:], seqFn())
+			h := sha256.Sum256(b[:])
+			sb.WriteString(hex.EncodeToString(h[:8]))
+		case "dirs":
+			width := len(strconv.Itoa(t.opts.fanout - 1))
+			for i := 0; i < t.opts.depth; i++ {
+				if i > 0 {
+					sb.WriteByte('/')
+				}
+				fmt.Fprintf(&sb, "%0*d", width, rng.Intn(t.opts.fanout))
+			}
+		case "utf8":
+			for i := 0; i < 8; i++ {
+				sb.WriteRune(utf8KeyRunes[rng.Intn(len(utf8KeyRunes))])
+			}
+		case "special":
+			for i := 0; i < 8; i++ {
+				sb.WriteRune(specialKeyRunes[rng.Intn(len(specialKeyRunes))])
+			}
+		}
+	}
+	if pad := t.opts.length - sb.Len(); pad > 0 {
+		b := make([]byte, pad)
+		randASCIIBytes(b, rng)
+		sb.Write(b)
+	}
+	return sb.String()
+}
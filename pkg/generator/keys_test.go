@@ -0,0 +1,88 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"math/rand"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestKeyTemplate(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	tests := []struct {
+		opts   KeyOpts
+		re     string
+		unique bool
+	}{
+		{opts: WithKeys(), re: `^obj$`},
+		{opts: WithKeys().Template("{seq}-{name}"), re: `^0000000000\d\d-obj$`, unique: true},
+		{opts: WithKeys().Template("{time}-{rand}"), re: `^\d{8}T\d{6}\.\d{9}Z-[a-zA-Z0-9()]{16}$`, unique: true},
+		{opts: WithKeys().Template("{hash}/{name}"), re: `^[0-9a-f]{16}/obj$`, unique: true},
+		{opts: WithKeys().Depth(3).Fanout(16), re: `^\d\d/\d\d/\d\d/obj$`},
+		{opts: WithKeys().Template("a/{dirs}/{seq}").Depth(2), re: `^a/\d/\d/\d{12}$`},
+		{opts: WithKeys().Length(1000), re: `^obj[a-zA-Z0-9()]{997}$`},
+	}
+	for _, test := range tests {
+		tmpl, err := newKeyTemplate(test.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		re := regexp.MustCompile(test.re)
+		seen := make(map[string]struct{})
+		for i := 0; i < 10; i++ {
+			key := tmpl.name(rng, "obj")
+			if !re.MatchString(key) {
+				t.Fatalf("template %q: key %q does not match %s", test.opts.template, key, test.re)
+			}
+			seen[key] = struct{}{}
+		}
+		if test.unique && len(seen) != 10 {
+			t.Errorf("template %q: %d unique keys, want 10", test.opts.template, len(seen))
+		}
+	}
+
+	tmpl, err := newKeyTemplate(WithKeys().Template("{utf8}{special}{seq}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := tmpl.name(rng, "obj")
+	if !utf8.ValidString(key) || utf8.RuneCountInString(key) != 28 {
+		t.Errorf("invalid utf8 key %q", key)
+	}
+	for _, r := range key[:len(key)-12] {
+		if r < utf8.RuneSelf && !strings.ContainsRune(string(specialKeyRunes), r) {
+			t.Errorf("unexpected character %q in %q", r, key)
+		}
+	}
+
+	for _, k := range []KeyOpts{
+		WithKeys().Template("{time}"),
+		WithKeys().Template("{nope}"),
+		WithKeys().Template("{name"),
+		WithKeys().Template("{dirs}/{name}"),
+		WithKeys().Length(2000),
+		WithKeys().Depth(2).Fanout(0),
+	} {
+		if _, err := newKeyTemplate(k); err == nil {
+			t.Errorf("template %q accepted", k.template)
+		}
+	}
+}
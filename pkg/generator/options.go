@@ -28,9 +28,16 @@ type Options struct {
 	src          func(o Options) (Source, error)
 	totalSize    int64
 	randSize     bool
+	sizes        SizeDistribution
 	csv          CsvOpts
 	random       RandomOpts
+	seeded       SeededOpts
+	compressible CompressibleOpts
+	keys         *keyTemplate
 	randomPrefix int
+	prefixFanout int
+	prefixSkew   float64
+	prefixes     *prefixSet
 }
 
 // OptionApplier allows to abstract generator options.
@@ -40,6 +47,9 @@ type OptionApplier interface {
 
 // getSize will return a size for an object.
 func (o Options) getSize(rng *rand.Rand) int64 {
+	if len(o.sizes) > 0 {
+		return o.sizes.Sample(rng)
+	}
 	if !o.randSize {
 		return o.totalSize
 	}
@@ -52,6 +62,8 @@ func defaultOptions() Options {
 		totalSize:    1 << 20,
 		csv:          csvOptsDefaults(),
 		random:       randomOptsDefaults(),
+		seeded:       seededOptsDefaults(),
+		compressible: compressibleOptsDefaults(),
 		randomPrefix: 0,
 	}
 	return o
@@ -0,0 +1,81 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/minio/warp/pkg/model"
+)
+
+// prefixSet is a fixed set of prefixes shared by all sources.
+// The same number of prefixes results in the same prefixes,
+// so several clients will spread objects over the same prefixes.
+type prefixSet struct {
+	prefixes []string
+	zipf     *model.Zipf
+}
+
+// WithPrefixFanout will spread objects over n prefixes independently of the number of sources.
+// With a skew of 0 prefixes are selected uniformly,
+// otherwise the n'th prefix is selected with a weight of 1/n^skew.
+// A prefix size must be set.
+func WithPrefixFanout(n int, skew float64) Option {
+	return func(o *Options) error {
+		if n <= 0 {
+			return errors.New("WithPrefixFanout: number of prefixes must be > 0")
+		}
+		if skew < 0 {
+			return errors.New("WithPrefixFanout: skew must be >= 0")
+		}
+		o.prefixFanout = n
+		o.prefixSkew = skew
+		return nil
+	}
+}
+
+func newPrefixSet(n, size int, skew float64) (*prefixSet, error) {
+	if size <= 0 {
+		return nil, errors.New("prefix fan-out requires a prefix size > 0")
+	}
+	p := prefixSet{
+		prefixes: make([]string, 0, n),
+		zipf:     model.NewZipf(n, skew),
+	}
+	seen := make(map[string]struct{}, n)
+	b := make([]byte, size)
+	for seed := int64(0); len(p.prefixes) < n; seed++ {
+		if seed > int64(n)*100 {
+			return nil, fmt.Errorf("unable to create %d unique prefixes of %d characters", n, size)
+		}
+		randASCIIBytes(b, rand.New(rand.NewSource(seed)))
+		if _, ok := seen[string(b)]; ok {
+			continue
+		}
+		seen[string(b)] = struct{}{}
+		p.prefixes = append(p.prefixes, string(b))
+	}
+	return &p, nil
+}
+
+// pick returns a prefix.
+func (p *prefixSet) pick(rng *rand.Rand) string {
+	return p.prefixes[p.zipf.Rank(rng)]
+}
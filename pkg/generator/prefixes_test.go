@@ -0,0 +1,79 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestPrefixFanout(t *testing.T) {
+	const n = 10
+	fn, err := NewFn(WithRandomData().Apply(), WithSize(1000), WithPrefixSize(8), WithPrefixFanout(n, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[string]int)
+	for i := 0; i < 4; i++ {
+		src := fn()
+		if p := src.Prefix(); p != "" {
+			t.Fatalf("source prefix %q, want none", p)
+		}
+		for j := 0; j < 10000; j++ {
+			obj := src.Object()
+			if !strings.HasPrefix(obj.Name, obj.Prefix+"/") {
+				t.Fatalf("object %q not in prefix %q", obj.Name, obj.Prefix)
+			}
+			counts[obj.Prefix]++
+		}
+	}
+	if len(counts) != n {
+		t.Fatalf("got %d prefixes, want %d", len(counts), n)
+	}
+
+	// The same prefixes are created every time.
+	p, err := newPrefixSet(n, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// With a skew of 1 the first prefix has twice the objects of the second.
+	if ratio := float64(counts[p.prefixes[0]]) / float64(counts[p.prefixes[1]]); math.Abs(ratio-2) > 0.2 {
+		t.Errorf("prefix 1/prefix 2 ratio %.2f, want 2", ratio)
+	}
+
+	uniform, err := newPrefixSet(n, 8, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rng := rand.New(rand.NewSource(0))
+	var first int
+	for i := 0; i < 10000; i++ {
+		if uniform.pick(rng) == uniform.prefixes[0] {
+			first++
+		}
+	}
+	if share := float64(first) / 10000; math.Abs(share-0.1) > 0.02 {
+		t.Errorf("uniform: %.3f of objects in first prefix, want 0.1", share)
+	}
+
+	if _, err := NewFn(WithRandomData().Apply(), WithPrefixFanout(n, 0)); err == nil {
+		t.Error("fan-out without prefix size accepted")
+	}
+}
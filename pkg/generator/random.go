@@ -130,7 +130,7 @@ func (r *randomSrc) Object() *Object {
 	var nBuf [16]byte
 	randASCIIBytes(nBuf[:], r.rng)
 	r.obj.Size = r.o.getSize(r.rng)
-	r.obj.setName(fmt.Sprintf("%d.%s.rnd", atomic.LoadUint64(&r.counter), string(nBuf[:])))
+	r.obj.setName(r.o, r.rng, fmt.Sprintf("%d.%s.rnd", atomic.LoadUint64(&r.counter), string(nBuf[:])))
 	data := r.buf.data
 	if int64(len(data)) > r.obj.Size {
 		data = data[:r.obj.Size]
@@ -172,5 +172,5 @@ func (r *randomSrc) String() string {
 }
 
 func (r *randomSrc) Prefix() string {
-	return r.obj.Prefix
+	return r.obj.sourcePrefix(r.o)
 }
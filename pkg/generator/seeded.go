@@ -0,0 +1,225 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sync/atomic"
+)
+
+// WithSeededData returns options for content that is a function of a seed and the object name.
+func WithSeededData() SeededOpts {
+	return seededOptsDefaults()
+}
+
+// Apply seeded data options.
+func (o SeededOpts) Apply() Option {
+	return func(opts *Options) error {
+		opts.seeded = o
+		opts.src = newSeeded
+		return nil
+	}
+}
+
+// Seed sets the seed of all generated content.
+func (o SeededOpts) Seed(s int64) SeededOpts {
+	o.seed = s
+	return o
+}
+
+// SeededOpts are the options for the seeded data source.
+type SeededOpts struct {
+	seed int64
+}
+
+func seededOptsDefaults() SeededOpts {
+	return SeededOpts{seed: 0}
+}
+
+// SeededContent identifies content that is a function of a seed,
+// an object name and a version.
+// The content can be regenerated by anyone knowing these.
+type SeededContent struct {
+	Seed    int64
+	Name    string
+	Version uint64
+}
+
+// cipher returns the block cipher generating the content.
+func (c SeededContent) cipher() cipher.Block {
+	h := sha256.New()
+	var tmp [8]byte
+	binary.LittleEndian.PutUint64(tmp[:], uint64(c.Seed))
+	h.Write(tmp[:])
+	binary.LittleEndian.PutUint64(tmp[:], c.Version)
+	h.Write(tmp[:])
+	io.WriteString(h, c.Name)
+	block, err := aes.NewCipher(h.Sum(nil))
+	if err != nil {
+		panic(err)
+	}
+	return block
+}
+
+// stream returns the content stream starting at offset.
+func (c SeededContent) stream(block cipher.Block, offset int64) cipher.Stream {
+	var iv [aes.BlockSize]byte
+	binary.BigEndian.PutUint64(iv[8:], uint64(offset/aes.BlockSize))
+	s := cipher.NewCTR(block, iv[:])
+	if skip := offset % aes.BlockSize; skip > 0 {
+		var tmp [aes.BlockSize]byte
+		s.XORKeyStream(tmp[:skip], tmp[:skip])
+	}
+	return s
+}
+
+// ReadAt fills p with the content at offset off.
+// The content has no end, so p is always filled.
+func (c SeededContent) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("SeededContent.ReadAt: negative offset")
+	}
+	for i := range p {
+		p[i] = 0
+	}
+	c.stream(c.cipher(), off).XORKeyStream(p, p)
+	return len(p), nil
+}
+
+// Reader returns a reader of the first size bytes of the content.
+func (c SeededContent) Reader(size int64) io.ReadSeeker {
+	block := c.cipher()
+	return &seededReader{
+		content: c,
+		block:   block,
+		stream:  c.stream(block, 0),
+		size:    size,
+	}
+}
+
+// SeededContentOf returns the content read by a reader returned by a seeded source.
+func SeededContentOf(r io.Reader) (SeededContent, bool) {
+	if sr, ok := r.(*seededReader); ok {
+		return sr.content, true
+	}
+	return SeededContent{}, false
+}
+
+// Reseed renames an object with seeded content and regenerates the content
+// for the new name and version, so every version of an object has different content.
+// Only the name is changed if the object doesn't have seeded content.
+func (o *Object) Reseed(name string, version uint64) {
+	o.Name = name
+	if c, ok := SeededContentOf(o.Reader); ok {
+		c.Name, c.Version = name, version
+		o.Reader = c.Reader(o.Size)
+	}
+}
+
+type seededReader struct {
+	content SeededContent
+	block   cipher.Block
+	stream  cipher.Stream
+	size    int64
+	pos     int64
+}
+
+func (r *seededReader) Read(p []byte) (int, error) {
+	remain := r.size - r.pos
+	if remain <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > remain {
+		p = p[:remain]
+	}
+	for i := range p {
+		p[i] = 0
+	}
+	r.stream.XORKeyStream(p, p)
+	r.pos += int64(len(p))
+	return len(p), nil
+}
+
+func (r *seededReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	default:
+		return 0, errors.New("seededReader.Seek: invalid whence")
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.pos
+	case io.SeekEnd:
+		offset += r.size
+	}
+	if offset < 0 {
+		return 0, errors.New("seededReader.Seek: negative position")
+	}
+	if offset > r.size {
+		return 0, io.EOF
+	}
+	r.pos = offset
+	r.stream = r.content.stream(r.block, offset)
+	return offset, nil
+}
+
+type seededSrc struct {
+	counter uint64
+	o       Options
+	rng     *rand.Rand
+	obj     Object
+}
+
+func newSeeded(o Options) (Source, error) {
+	s := seededSrc{
+		o: o,
+		// Names must not collide between clients using the same seed.
+		rng: rand.New(rand.NewSource(int64(rand.Uint64()))),
+		obj: Object{
+			ContentType: "application/octet-stream",
+		},
+	}
+	s.obj.setPrefix(o)
+	return &s, nil
+}
+
+func (s *seededSrc) Object() *Object {
+	atomic.AddUint64(&s.counter, 1)
+	var nBuf [16]byte
+	randASCIIBytes(nBuf[:], s.rng)
+	s.obj.Size = s.o.getSize(s.rng)
+	s.obj.setName(s.o, s.rng, fmt.Sprintf("%d.%s.rnd", atomic.LoadUint64(&s.counter), string(nBuf[:])))
+	s.obj.Reader = SeededContent{Seed: s.o.seeded.seed, Name: s.obj.Name}.Reader(s.obj.Size)
+	return &s.obj
+}
+
+func (s *seededSrc) String() string {
+	if s.o.randSize {
+		return fmt.Sprintf("Seeded data (seed %d); random size up to %d bytes", s.o.seeded.seed, s.o.totalSize)
+	}
+	return fmt.Sprintf("Seeded data (seed %d); %d bytes total", s.o.seeded.seed, s.o.totalSize)
+}
+
+func (s *seededSrc) Prefix() string {
+	return s.obj.sourcePrefix(s.o)
+}
@@ -0,0 +1,80 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func TestSeeded(t *testing.T) {
+	const size = 100000
+	src, err := New(WithSeededData().Seed(42).Apply(), WithSize(size))
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := src.Object()
+	got, err := ioutil.ReadAll(obj.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != size {
+		t.Fatalf("want size %d, got %d", size, len(got))
+	}
+
+	// Content must only depend on seed, name and version.
+	content, ok := SeededContentOf(obj.Reader)
+	if !ok {
+		t.Fatal("reader is not seeded")
+	}
+	want, err := ioutil.ReadAll(SeededContent{Seed: 42, Name: obj.Name}.Reader(size))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatal("content was not regenerated")
+	}
+	other := make([]byte, 1000)
+	for _, c := range []SeededContent{{Seed: 43, Name: obj.Name}, {Seed: 42, Name: obj.Name, Version: 1}, {Seed: 42, Name: obj.Name + "x"}} {
+		c.ReadAt(other, 0)
+		if bytes.Equal(other, got[:len(other)]) {
+			t.Errorf("%+v returned the same content", c)
+		}
+	}
+
+	// Seek and ReadAt at unaligned offsets.
+	for _, off := range []int64{0, 1, 15, 16, 17, 4095, 99999} {
+		if _, err := obj.Reader.Seek(off, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadAll(obj.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b, got[off:]) {
+			t.Errorf("offset %d: content after seek differs", off)
+		}
+		b = make([]byte, size-off)
+		content.ReadAt(b, off)
+		if !bytes.Equal(b, got[off:]) {
+			t.Errorf("offset %d: ReadAt content differs", off)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+)
+
+// SizeBucket is a range of object sizes from Min up to and including Max.
+type SizeBucket struct {
+	Min, Max int64
+	// Weight is the relative share of objects in the bucket.
+	Weight float64
+}
+
+// SizeDistribution is a weighted list of object size ranges.
+type SizeDistribution []SizeBucket
+
+// ParseSizeDistribution parses a comma separated list of sizes with weights.
+// Each entry is 'size:weight' or 'min-max:weight', for example '4KiB:50,1MiB-4MiB:30,64MiB:1'.
+// The weight can be omitted and defaults to 1.
+func ParseSizeDistribution(s string) (SizeDistribution, error) {
+	var d SizeDistribution
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		b := SizeBucket{Weight: 1}
+		sizes := entry
+		if i := strings.LastIndexByte(entry, ':'); i >= 0 {
+			w, err := strconv.ParseFloat(strings.TrimSpace(entry[i+1:]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("size %q: invalid weight: %w", entry, err)
+			}
+			b.Weight = w
+			sizes = entry[:i]
+		}
+		lo, hi := sizes, sizes
+		if i := strings.IndexByte(sizes, '-'); i >= 0 {
+			lo, hi = sizes[:i], sizes[i+1:]
+		}
+		var err error
+		if b.Min, err = parseSize(lo); err != nil {
+			return nil, fmt.Errorf("size %q: %w", entry, err)
+		}
+		if b.Max, err = parseSize(hi); err != nil {
+			return nil, fmt.Errorf("size %q: %w", entry, err)
+		}
+		d = append(d, b)
+	}
+	return d, d.Validate()
+}
+
+// ReadSizeHistogram reads a size distribution from a CSV histogram.
+// Each record is either 'size,weight' or 'min,max,weight'.
+// Sizes can have units. A header line is skipped and lines starting with '#' are ignored.
+func ReadSizeHistogram(r io.Reader) (SizeDistribution, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	var d SizeDistribution
+	for line := 1; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		var b SizeBucket
+		switch len(rec) {
+		case 2:
+			b.Min, err = parseSize(rec[0])
+			b.Max = b.Min
+		case 3:
+			b.Min, err = parseSize(rec[0])
+			if err == nil {
+				b.Max, err = parseSize(rec[1])
+			}
+		default:
+			return nil, fmt.Errorf("line %d: expected 2 or 3 fields, got %d", line, len(rec))
+		}
+		if err == nil {
+			b.Weight, err = strconv.ParseFloat(rec[len(rec)-1], 64)
+		}
+		if err != nil {
+			if line == 1 && len(d) == 0 {
+				// Header
+				continue
+			}
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		d = append(d, b)
+	}
+	return d, d.Validate()
+}
+
+// parseSize parses a size with optional units.
+func parseSize(s string) (int64, error) {
+	n, err := humanize.ParseBytes(strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	return int64(n), nil
+}
+
+// Validate returns an error if the distribution cannot be sampled.
+func (d SizeDistribution) Validate() error {
+	if len(d) == 0 {
+		return errors.New("size distribution: no sizes specified")
+	}
+	var total float64
+	for _, b := range d {
+		if b.Min <= 0 || b.Max < b.Min {
+			return fmt.Errorf("size distribution: invalid size range %d-%d", b.Min, b.Max)
+		}
+		if b.Weight < 0 {
+			return fmt.Errorf("size distribution: negative weight %v", b.Weight)
+		}
+		total += b.Weight
+	}
+	if total <= 0 {
+		return errors.New("size distribution: total weight must be > 0")
+	}
+	return nil
+}
+
+// Sample returns a random size.
+// A bucket is selected by weight and the size is uniform within it.
+func (d SizeDistribution) Sample(rng *rand.Rand) int64 {
+	var total float64
+	for _, b := range d {
+		total += b.Weight
+	}
+	x := rng.Float64() * total
+	b := d[len(d)-1]
+	for _, v := range d {
+		if x < v.Weight {
+			b = v
+			break
+		}
+		x -= v.Weight
+	}
+	if b.Max <= b.Min {
+		return b.Min
+	}
+	return b.Min + rng.Int63n(b.Max-b.Min+1)
+}
+
+// MaxSize returns the biggest size of the distribution.
+func (d SizeDistribution) MaxSize() int64 {
+	var max int64
+	for _, b := range d {
+		if b.Max > max {
+			max = b.Max
+		}
+	}
+	return max
+}
+
+// Bounds returns the sorted boundaries of the buckets.
+// Each bucket covers sizes from one boundary up to, but not including, a following boundary.
+func (d SizeDistribution) Bounds() []int64 {
+	seen := make(map[int64]struct{}, len(d)*2)
+	res := make([]int64, 0, len(d)*2)
+	for _, b := range d {
+		for _, v := range []int64{b.Min, b.Max + 1} {
+			if _, ok := seen[v]; !ok {
+				seen[v] = struct{}{}
+				res = append(res, v)
+			}
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
+
+// WithSizeDistribution will sample object sizes from the distribution.
+// This overrides the size and random size options.
+func WithSizeDistribution(d SizeDistribution) Option {
+	return func(o *Options) error {
+		if err := d.Validate(); err != nil {
+			return fmt.Errorf("WithSizeDistribution: %w", err)
+		}
+		o.sizes = d
+		o.totalSize = d.MaxSize()
+		o.randSize = true
+		return nil
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package generator
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSizeDistribution(t *testing.T) {
+	d, err := ParseSizeDistribution("4KiB:75, 1MiB-2MiB:25")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := SizeDistribution{{Min: 4 << 10, Max: 4 << 10, Weight: 75}, {Min: 1 << 20, Max: 2 << 20, Weight: 25}}
+	if !reflect.DeepEqual(d, want) {
+		t.Fatalf("got %+v, want %+v", d, want)
+	}
+	if got, want := d.Bounds(), []int64{4 << 10, 4<<10 + 1, 1 << 20, 2<<20 + 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("bounds: got %v, want %v", got, want)
+	}
+
+	rng := rand.New(rand.NewSource(0))
+	const samples = 10000
+	var small int
+	for i := 0; i < samples; i++ {
+		switch n := d.Sample(rng); {
+		case n == 4<<10:
+			small++
+		case n < 1<<20 || n > 2<<20:
+			t.Fatalf("sample %d outside distribution", n)
+		}
+	}
+	if share := float64(small) / samples; math.Abs(share-0.75) > 0.02 {
+		t.Errorf("%.3f of samples in first bucket, want 0.75", share)
+	}
+
+	for _, s := range []string{"", "4KiB:x", "2MiB-1MiB", "0", "1KiB:0"} {
+		if _, err := ParseSizeDistribution(s); err == nil {
+			t.Errorf("%q accepted", s)
+		}
+	}
+}
+
+func TestReadSizeHistogram(t *testing.T) {
+	const input = `min,max,weight
+# Small objects
+1,1023,10
+1KiB,1MiB,5
+10MiB,10MiB,1
+`
+	d, err := ReadSizeHistogram(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := SizeDistribution{{Min: 1, Max: 1023, Weight: 10}, {Min: 1 << 10, Max: 1 << 20, Weight: 5}, {Min: 10 << 20, Max: 10 << 20, Weight: 1}}
+	if !reflect.DeepEqual(d, want) {
+		t.Fatalf("got %+v, want %+v", d, want)
+	}
+	if _, err := ReadSizeHistogram(strings.NewReader("1KiB,1\n2KiB,x\n")); err == nil {
+		t.Error("invalid weight accepted")
+	}
+}
@@ -0,0 +1,174 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package model
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/minio/warp/pkg/accesslog"
+)
+
+// maxRateSamples is the maximum number of arrival rate samples.
+const maxRateSamples = 1000
+
+// Fit creates a model of the requests in the access log entries.
+// Only PUT, GET, HEAD and DELETE requests are used.
+func Fit(entries accesslog.Entries) (*Model, error) {
+	reqs := make(accesslog.Entries, 0, len(entries))
+	for _, e := range entries {
+		switch e.Method {
+		case http.MethodGet, http.MethodPut, http.MethodHead, http.MethodDelete:
+			if !e.Time.IsZero() {
+				reqs = append(reqs, e)
+			}
+		}
+	}
+	if len(reqs) == 0 {
+		return nil, errors.New("no requests with a time found")
+	}
+	reqs.SortByTime()
+
+	m := Model{
+		Version:  Version,
+		Requests: len(reqs),
+		Ops:      make(map[string]*Op),
+	}
+	start := reqs[0].Time
+	for _, e := range reqs {
+		if d := e.Time.Add(e.Latency).Sub(start); d > m.Duration {
+			m.Duration = d
+		}
+	}
+	if m.Duration < time.Second {
+		m.Duration = time.Second
+	}
+
+	var (
+		counts  = make(map[string]int)
+		errs    = make(map[string]int)
+		sizes   = make(map[string][]int64)
+		written = make(map[string]time.Time)
+		reads   = make(map[string]int)
+		delays  []int64
+		nReads  int
+	)
+	for _, e := range reqs {
+		counts[e.Method]++
+		if e.Err {
+			errs[e.Method]++
+			continue
+		}
+		switch e.Method {
+		case http.MethodPut:
+			sizes[e.Method] = append(sizes[e.Method], e.Size)
+			written[e.Key] = e.Time
+		case http.MethodDelete:
+			delete(written, e.Key)
+		case http.MethodGet, http.MethodHead:
+			if e.Method == http.MethodGet {
+				sizes[e.Method] = append(sizes[e.Method], e.Size)
+			}
+			nReads++
+			if t, ok := written[e.Key]; ok {
+				delays = append(delays, int64(e.Time.Sub(t)))
+				continue
+			}
+			reads[e.Key]++
+		}
+	}
+	for method, n := range counts {
+		op := Op{
+			Share:    float64(n) / float64(len(reqs)),
+			ErrRatio: float64(errs[method]) / float64(n),
+		}
+		if s := sizes[method]; len(s) > 0 {
+			op.Sizes = newHistogram(s)
+		}
+		m.Ops[method] = &op
+	}
+	m.Popularity = fitPopularity(reads)
+	if nReads > 0 && len(delays) > 0 {
+		m.ReadAfterWrite = ReadAfterWrite{
+			Ratio: float64(len(delays)) / float64(nReads),
+			Delay: newHistogram(delays),
+		}
+	}
+	m.Rate = fitRate(reqs, m.Duration)
+	return &m, nil
+}
+
+// fitPopularity fits a Zipf distribution to the number of reads of each object.
+// A straight line is fitted to log(reads) as a function of log(rank).
+func fitPopularity(reads map[string]int) Popularity {
+	p := Popularity{Keys: len(reads)}
+	if len(reads) < 2 {
+		return p
+	}
+	counts := make([]int, 0, len(reads))
+	for _, n := range reads {
+		counts = append(counts, n)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(counts)))
+	var sx, sy, sxx, sxy float64
+	for i, n := range counts {
+		x := math.Log(float64(i + 1))
+		y := math.Log(float64(n))
+		sx += x
+		sy += y
+		sxx += x * x
+		sxy += x * y
+	}
+	n := float64(len(counts))
+	div := n*sxx - sx*sx
+	if div == 0 {
+		return p
+	}
+	slope := (n*sxy - sx*sy) / div
+	if slope < 0 {
+		p.Skew = -slope
+	}
+	return p
+}
+
+// fitRate returns the arrival rate of the requests.
+func fitRate(reqs accesslog.Entries, d time.Duration) Rate {
+	interval := time.Second
+	if d > maxRateSamples*time.Second {
+		interval = (d/maxRateSamples + time.Second - 1).Truncate(time.Second)
+	}
+	r := Rate{
+		Interval:  interval,
+		PerSecond: make([]float64, (d+interval-1)/interval),
+	}
+	start := reqs[0].Time
+	for _, e := range reqs {
+		idx := int(e.Time.Sub(start) / interval)
+		if idx >= len(r.PerSecond) {
+			idx = len(r.PerSecond) - 1
+		}
+		r.PerSecond[idx]++
+	}
+	for i := range r.PerSecond {
+		r.PerSecond[i] /= interval.Seconds()
+	}
+	return r
+}
@@ -0,0 +1,308 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package model describes the shape of a workload,
+// so it can be reproduced without replaying the original requests.
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+	"math/rand"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+)
+
+// Version is the current version of the model format.
+const Version = 1
+
+// Model describes the shape of a workload.
+type Model struct {
+	Version int `json:"version"`
+	// Duration of the modeled requests.
+	Duration time.Duration `json:"duration_ns"`
+	// Requests is the number of modeled requests.
+	Requests int `json:"requests"`
+	// Ops contains the model of each HTTP method.
+	Ops map[string]*Op `json:"ops"`
+	// Popularity of objects that existed before the first request.
+	Popularity Popularity `json:"popularity"`
+	// ReadAfterWrite models reads of objects written during the workload.
+	ReadAfterWrite ReadAfterWrite `json:"read_after_write"`
+	// Rate is the arrival rate of requests over time.
+	Rate Rate `json:"rate"`
+}
+
+// Op models requests of a single HTTP method.
+type Op struct {
+	// Share of all requests.
+	Share float64 `json:"share"`
+	// ErrRatio is the ratio of requests that failed.
+	ErrRatio float64 `json:"err_ratio"`
+	// Sizes of transferred objects.
+	Sizes Histogram `json:"sizes,omitempty"`
+}
+
+// Popularity describes how reads are distributed across objects.
+type Popularity struct {
+	// Keys is the number of distinct objects read.
+	Keys int `json:"keys"`
+	// Skew is the exponent of a Zipf distribution fitted to the reads.
+	// 0 means all objects are equally popular.
+	Skew float64 `json:"skew"`
+}
+
+// ReadAfterWrite describes reads of recently written objects.
+type ReadAfterWrite struct {
+	// Ratio of reads that read an object written during the workload.
+	Ratio float64 `json:"ratio"`
+	// Delay between the write and the read in nanoseconds.
+	Delay Histogram `json:"delay,omitempty"`
+}
+
+// Rate is the number of requests per second over time.
+type Rate struct {
+	// Interval of each sample.
+	Interval time.Duration `json:"interval_ns"`
+	// PerSecond contains the average requests per second of each interval.
+	PerSecond []float64 `json:"per_second"`
+}
+
+// Bucket is a histogram bucket containing values from Min up to and including Max.
+type Bucket struct {
+	Min    int64   `json:"min"`
+	Max    int64   `json:"max"`
+	Weight float64 `json:"weight"`
+}
+
+// Histogram is a histogram with buckets of exponentially increasing size.
+type Histogram []Bucket
+
+// Load reads a model.
+func Load(r io.Reader) (*Model, error) {
+	var m Model
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, err
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// LoadFile reads a model from a file.
+func LoadFile(name string) (*Model, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// Save writes the model as indented JSON.
+func (m *Model) Save(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(m)
+}
+
+// Validate returns an error if the model cannot be used.
+func (m *Model) Validate() error {
+	if m.Version != Version {
+		return fmt.Errorf("unsupported model version %d", m.Version)
+	}
+	if len(m.Ops) == 0 {
+		return errors.New("model contains no operations")
+	}
+	for method, op := range m.Ops {
+		switch method {
+		case http.MethodGet, http.MethodPut, http.MethodHead, http.MethodDelete:
+		default:
+			return fmt.Errorf("unsupported method %q in model", method)
+		}
+		if op.Share < 0 {
+			return fmt.Errorf("negative share for %s", method)
+		}
+	}
+	if m.Rate.Interval <= 0 || m.MeanRate() <= 0 {
+		return errors.New("model contains no arrival rate")
+	}
+	return nil
+}
+
+// MaxSize returns the biggest object size of any operation.
+func (m *Model) MaxSize() int64 {
+	var max int64
+	for _, op := range m.Ops {
+		if n := len(op.Sizes); n > 0 && op.Sizes[n-1].Max > max {
+			max = op.Sizes[n-1].Max
+		}
+	}
+	return max
+}
+
+// MeanRate returns the average number of requests per second.
+func (m *Model) MeanRate() float64 {
+	if len(m.Rate.PerSecond) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range m.Rate.PerSecond {
+		sum += v
+	}
+	return sum / float64(len(m.Rate.PerSecond))
+}
+
+// RateAt returns the requests per second at a time since the start.
+// The rate wraps around when the time is beyond the modeled duration.
+func (r Rate) RateAt(d time.Duration) float64 {
+	if len(r.PerSecond) == 0 || r.Interval <= 0 {
+		return 0
+	}
+	idx := int(d/r.Interval) % len(r.PerSecond)
+	return r.PerSecond[idx]
+}
+
+// newHistogram returns a histogram of the values.
+// Buckets are powers of 2 and empty buckets are omitted.
+func newHistogram(values []int64) Histogram {
+	counts := make(map[int]int)
+	for _, v := range values {
+		counts[bucketIdx(v)]++
+	}
+	res := make(Histogram, 0, len(counts))
+	for idx, n := range counts {
+		b := Bucket{Weight: float64(n) / float64(len(values))}
+		if idx > 0 {
+			b.Min = int64(1) << (idx - 1)
+			b.Max = int64(1)<<idx - 1
+		}
+		res = append(res, b)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Min < res[j].Min
+	})
+	return res
+}
+
+// bucketIdx returns 0 for values <= 0, otherwise the number of bits in v.
+func bucketIdx(v int64) int {
+	if v <= 0 {
+		return 0
+	}
+	return bits.Len64(uint64(v))
+}
+
+// Sample returns a random value from the histogram.
+// Values are distributed evenly within each bucket.
+func (h Histogram) Sample(rng *rand.Rand) int64 {
+	if len(h) == 0 {
+		return 0
+	}
+	var total float64
+	for _, b := range h {
+		total += b.Weight
+	}
+	x := rng.Float64() * total
+	b := h[len(h)-1]
+	for _, v := range h {
+		if x < v.Weight {
+			b = v
+			break
+		}
+		x -= v.Weight
+	}
+	if b.Max <= b.Min {
+		return b.Min
+	}
+	return b.Min + rng.Int63n(b.Max-b.Min+1)
+}
+
+// Mean returns the approximate mean value of the histogram.
+func (h Histogram) Mean() float64 {
+	var sum, total float64
+	for _, b := range h {
+		sum += b.Weight * (float64(b.Min) + float64(b.Max)) / 2
+		total += b.Weight
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
+
+// Zipf samples ranks from a Zipf distribution.
+// Unlike rand.Zipf any non-negative exponent is accepted.
+// Rank n is selected with a weight of 1/(n+1)^s, so an exponent of 0 selects all ranks uniformly.
+// A Zipf is safe for concurrent use.
+type Zipf struct {
+	// cdf is the cumulative weight of each rank.
+	cdf []float64
+}
+
+// NewZipf returns a sampler of ranks 0 to n-1 with exponent s.
+func NewZipf(n int, s float64) *Zipf {
+	z := Zipf{cdf: make([]float64, n)}
+	var sum float64
+	for i := range z.cdf {
+		sum += 1 / math.Pow(float64(i+1), s)
+		z.cdf[i] = sum
+	}
+	return &z
+}
+
+// N returns the number of ranks.
+func (z *Zipf) N() int {
+	return len(z.cdf)
+}
+
+// Rank returns a random rank.
+func (z *Zipf) Rank(rng *rand.Rand) int {
+	return z.RankN(rng, len(z.cdf))
+}
+
+// RankN returns a random rank out of the first n ranks.
+// n cannot be bigger than N.
+func (z *Zipf) RankN(rng *rand.Rand, n int) int {
+	return z.search(rng.Float64(), n)
+}
+
+// RankAt returns the rank at u, which must be >= 0 and < 1.
+// This can be used to assign ranks from hashes instead of random numbers.
+func (z *Zipf) RankAt(u float64) int {
+	return z.search(u, len(z.cdf))
+}
+
+// search returns the rank at u of the first n ranks.
+func (z *Zipf) search(u float64, n int) int {
+	if n <= 0 {
+		return 0
+	}
+	cdf := z.cdf[:n]
+	i := sort.SearchFloat64s(cdf, u*cdf[n-1])
+	if i >= n {
+		i = n - 1
+	}
+	return i
+}
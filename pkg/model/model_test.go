@@ -0,0 +1,96 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/minio/warp/pkg/accesslog"
+)
+
+func TestFit(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	zipf := NewZipf(1000, 1.2)
+	start := time.Date(2021, 6, 9, 10, 0, 0, 0, time.UTC)
+	var entries accesslog.Entries
+	for i := 0; i < 100000; i++ {
+		now := start.Add(time.Duration(i) * 10 * time.Millisecond)
+		switch i % 4 {
+		case 0:
+			// Upload, read back 1 second later.
+			key := fmt.Sprint("new-", i)
+			entries = append(entries,
+				accesslog.Entry{Time: now, Method: "PUT", Key: key, Size: 1 << 20},
+				accesslog.Entry{Time: now.Add(time.Second), Method: "HEAD", Key: key},
+			)
+		default:
+			entries = append(entries, accesslog.Entry{Time: now, Method: "GET", Key: fmt.Sprint("old-", zipf.Rank(rng)), Size: 1000})
+		}
+	}
+	m, err := Fit(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Ops["GET"].Share; math.Abs(got-0.6) > 0.01 {
+		t.Errorf("GET share: want 0.6, got %v", got)
+	}
+	if got := m.Ops["PUT"].Sizes.Mean(); got < 1<<20 || got >= 2<<20 {
+		t.Errorf("PUT mean size: got %v", got)
+	}
+	if got := m.Popularity.Skew; math.Abs(got-1.2) > 0.3 {
+		t.Errorf("skew: want 1.2, got %v", got)
+	}
+	if got := m.ReadAfterWrite.Ratio; math.Abs(got-0.25) > 0.01 {
+		t.Errorf("read after write ratio: want 0.25, got %v", got)
+	}
+	if got := time.Duration(m.ReadAfterWrite.Delay.Mean()); got < 500*time.Millisecond || got > 2*time.Second {
+		t.Errorf("read after write delay: want 1s, got %v", got)
+	}
+	// 125 requests per second.
+	if got := m.MeanRate(); math.Abs(got-125) > 5 {
+		t.Errorf("rate: want 125, got %v", got)
+	}
+
+	var buf bytes.Buffer
+	if err := m.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	m2, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m2.Requests != m.Requests || m2.Popularity != m.Popularity {
+		t.Errorf("model changed after load")
+	}
+}
+
+func TestHistogramSample(t *testing.T) {
+	h := newHistogram([]int64{0, 100, 100, 5000})
+	rng := rand.New(rand.NewSource(0))
+	for i := 0; i < 1000; i++ {
+		v := h.Sample(rng)
+		if v != 0 && (v < 64 || v > 127) && (v < 4096 || v > 8191) {
+			t.Fatalf("sample %d outside buckets %+v", v, h)
+		}
+	}
+}